@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -46,6 +47,17 @@ func handler(ctx context.Context, event json.RawMessage) error {
 		}
 	}
 
+	for _, playerId := range req.NoShowPlayerIds {
+		err = storageClient.IncrementMissedFirstMoves(ctx, playerId, time.Now())
+		if err != nil {
+			return fmt.Errorf(
+				"failed to record missed first move: [userId: %s] - %w",
+				playerId,
+				err,
+			)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	lambdaService "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/chess-vn/slchess/internal/app/adjourned"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+// Leaves a server that still holds the match in memory the time to adjourn or score it itself
+const expiryGracePeriod = 10 * time.Minute
+
+var (
+	storageClient *storage.Client
+	lambdaClient  *lambdaService.Client
+
+	endGameFunctionArn = os.Getenv("END_GAME_FUNCTION_ARN")
+)
+
+func init() {
+	cfg, _ := config.LoadDefaultConfig(context.TODO())
+	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
+	lambdaClient = lambdaService.NewFromConfig(cfg)
+}
+
+// handler function    scores the adjourned matches nobody resumed before their deadline
+func handler(ctx context.Context, event events.CloudWatchEvent) error {
+	expiredBefore := time.Now().Add(-expiryGracePeriod)
+	var (
+		lastKey map[string]dynamodbTypes.AttributeValue
+		errs    []error
+	)
+	for {
+		activeMatches, nextKey, err := storageClient.ScanAdjournedMatches(ctx, lastKey)
+		if err != nil {
+			return fmt.Errorf("failed to scan adjourned matches: %w", err)
+		}
+		for _, activeMatch := range activeMatches {
+			if activeMatch.Adjournment.Deadline.After(expiredBefore) {
+				continue
+			}
+			// One match that cannot be scored must not hold back the ones after it
+			if err := endMatch(ctx, activeMatch); err != nil {
+				err = fmt.Errorf("failed to end match: [matchId: %s] - %w", activeMatch.MatchId, err)
+				log.Print(err)
+				errs = append(errs, err)
+				continue
+			}
+			log.Printf("adjournment expired: %s", activeMatch.MatchId)
+		}
+		if nextKey == nil {
+			return errors.Join(errs...)
+		}
+		lastKey = nextKey
+	}
+}
+
+// endMatch function    records the match as a draw through the end game function, like the server does
+func endMatch(ctx context.Context, activeMatch entities.ActiveMatch) error {
+	matchRecordReq, err := adjourned.Record(activeMatch)
+	if err != nil {
+		return fmt.Errorf("failed to score adjourned match: %w", err)
+	}
+	payload, err := json.Marshal(matchRecordReq)
+	if err != nil {
+		return fmt.Errorf("failed to marshal match record request: %w", err)
+	}
+	output, err := lambdaClient.Invoke(ctx, &lambdaService.InvokeInput{
+		FunctionName:   aws.String(endGameFunctionArn),
+		Payload:        payload,
+		InvocationType: types.InvocationTypeRequestResponse,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invoke end game: %w", err)
+	}
+	if output.FunctionError != nil {
+		return fmt.Errorf("end game failed: %s", *output.FunctionError)
+	}
+	return nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"context"
-	"crypto/rsa"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -14,12 +14,12 @@ import (
 	"github.com/chess-vn/slchess/internal/aws/auth"
 	"github.com/chess-vn/slchess/internal/aws/storage"
 	"github.com/chess-vn/slchess/internal/domains/entities"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	storageClient     *storage.Client
-	cognitoPublicKeys map[string]*rsa.PublicKey
+	storageClient  *storage.Client
+	tokenValidator *auth.Validator
+	guestSecret    []byte
 )
 
 func init() {
@@ -28,15 +28,8 @@ func init() {
 		panic("couldn't load config")
 	}
 	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
-	tokenSigningKeyUrl := fmt.Sprintf(
-		"https://cognito-idp.%s.amazonaws.com/%s/.well-known/jwks.json",
-		os.Getenv("AWS_REGION"),
-		os.Getenv("COGNITO_USER_POOL_ID"),
-	)
-	cognitoPublicKeys, err = auth.LoadCognitoPublicKeys(tokenSigningKeyUrl)
-	if err != nil {
-		panic("coulnd't load cognito public keys")
-	}
+	tokenValidator = auth.NewCognitoValidator()
+	guestSecret, _ = auth.GuestTokenSecret()
 }
 
 // Handle WebSocket connection with authentication
@@ -48,8 +41,8 @@ func handler(
 	error,
 ) {
 	token := event.Headers["Authorization"]
-	validToken, err := auth.ValidateJwt(token, cognitoPublicKeys)
-	if err != nil || !validToken.Valid {
+	userId, err := authenticate(token)
+	if err != nil {
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusUnauthorized,
 		}, fmt.Errorf("failed to validate token: %w", err)
@@ -58,7 +51,7 @@ func handler(
 	// Store connection in DynamoDB
 	connection := entities.Connection{
 		Id:     event.RequestContext.ConnectionID,
-		UserId: validToken.Claims.(jwt.MapClaims)["sub"].(string),
+		UserId: userId,
 	}
 	if err = storageClient.PutConnection(ctx, connection); err != nil {
 		return events.APIGatewayProxyResponse{
@@ -66,11 +59,35 @@ func handler(
 		}, fmt.Errorf("failed to put conntection: %w", err)
 	}
 
+	// Opening the queueing socket counts as a heartbeat of the user's ticket
+	_, err = storageClient.RefreshMatchmakingTicket(ctx, userId, time.Now())
+	if err != nil && !errors.Is(err, storage.ErrMatchmakingTicketNotFound) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to refresh matchmaking ticket: %w", err)
+	}
+
 	return events.APIGatewayProxyResponse{
 		StatusCode: http.StatusOK,
 	}, nil
 }
 
+// authenticate function    accepts a Cognito token, or a guest token waiting on the casual pool
+func authenticate(token string) (string, error) {
+	claims, err := tokenValidator.Validate(token)
+	if err == nil {
+		return claims.Subject, nil
+	}
+	if len(guestSecret) == 0 {
+		return "", err
+	}
+	guestId, guestErr := auth.GuestAuth(map[string]string{"Authorization": token}, guestSecret)
+	if guestErr != nil {
+		return "", errors.Join(err, guestErr)
+	}
+	return guestId, nil
+}
+
 func main() {
 	lambda.Start(handler)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -28,12 +29,32 @@ func handler(
 	error,
 ) {
 	connectionId := request.RequestContext.ConnectionID
-	err := storageClient.DeleteConnection(ctx, connectionId)
+	connection, err := storageClient.GetConnection(ctx, connectionId)
+	if err != nil && !errors.Is(err, storage.ErrConnectionNotFound) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get connection: %w", err)
+	}
+	err = storageClient.DeleteConnection(ctx, connectionId)
 	if err != nil {
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusInternalServerError,
 		}, fmt.Errorf("failed to delete connection: %w", err)
 	}
+
+	// Leaving the queue with the last open socket takes the user's ticket off it
+	if connection.UserId != "" {
+		_, err = storageClient.GetConnectionByUserId(ctx, connection.UserId)
+		if errors.Is(err, storage.ErrConnectionNotFound) {
+			err = storageClient.DeleteMatchmakingTicket(ctx, connection.UserId)
+		}
+		if err != nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusInternalServerError,
+			}, fmt.Errorf("failed to delete matchmaking ticket: %w", err)
+		}
+	}
+
 	return events.APIGatewayProxyResponse{
 		StatusCode: http.StatusOK,
 	}, nil
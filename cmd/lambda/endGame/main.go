@@ -13,6 +13,7 @@ import (
 	"github.com/chess-vn/slchess/internal/aws/storage"
 	"github.com/chess-vn/slchess/internal/domains/dtos"
 	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/rating"
 )
 
 var storageClient *storage.Client
@@ -28,6 +29,10 @@ func handler(ctx context.Context, event json.RawMessage) error {
 		return fmt.Errorf("failed to unmarshal request: %w", err)
 	}
 	matchRecord := dtos.MatchRecordRequestToEntity(matchRecordReq)
+	category, err := entities.RatingCategoryOf(matchRecordReq.GameMode)
+	if err != nil {
+		return fmt.Errorf("failed to get rating category: %w", err)
+	}
 
 	for _, player := range matchRecordReq.Players {
 		err := storageClient.DeleteUserMatch(ctx, player.Id)
@@ -40,7 +45,7 @@ func handler(ctx context.Context, event json.RawMessage) error {
 		}
 	}
 
-	err := storageClient.DeleteActiveMatch(ctx, matchRecord.MatchId)
+	err = storageClient.DeleteActiveMatch(ctx, matchRecord.MatchId)
 	if err != nil {
 		return fmt.Errorf("failed to delete active match: %w", err)
 	}
@@ -51,25 +56,55 @@ func handler(ctx context.Context, event json.RawMessage) error {
 	}
 
 	for i, player := range matchRecordReq.Players {
-		err = storageClient.UpdateUserRating(ctx, player.Id, storage.UserRatingUpdateOptions{
-			Rating: aws.Float64(player.NewRating),
-			RD:     aws.Float64(player.NewRD),
-		})
-		if err != nil {
-			return fmt.Errorf(
-				"failed to put player rating: [userId: %s] - %w",
+		// Unrated players keep the rating and its inactivity clock as they were
+		if !player.Unrated {
+			// The server keeps team members within the RD bounds, so this only fills in a missing volatility
+			newRating := rating.DefaultConfig.Clamp(rating.Rating{
+				Rating:     player.NewRating,
+				RD:         player.NewRD,
+				Volatility: player.NewVolatility,
+			})
+			err = storageClient.UpdateUserRating(ctx, player.Id, category, storage.UserRatingUpdateOptions{
+				Rating:     aws.Float64(newRating.Rating),
+				RD:         aws.Float64(newRating.RD),
+				Volatility: aws.Float64(newRating.Volatility),
+				RatedAt:    aws.Time(matchRecordReq.EndedAt),
+			})
+			if err != nil {
+				return fmt.Errorf(
+					"failed to put player rating: [userId: %s] - %w",
+					player.Id,
+					err,
+				)
+			}
+
+			err = storageClient.PutRatingHistoryEntry(ctx, entities.NewRatingHistoryEntry(
 				player.Id,
-				err,
-			)
+				category,
+				matchRecordReq.MatchId,
+				player.OldRating,
+				newRating.Rating,
+				newRating.RD,
+				matchRecordReq.EndedAt,
+			))
+			if err != nil {
+				return fmt.Errorf(
+					"failed to put rating history entry: [userId: %s] - %w",
+					player.Id,
+					err,
+				)
+			}
 		}
 
+		opponent := opposingTeam(matchRecordReq.Players, player.Team)
 		playerMatchResult := entities.MatchResult{
 			UserId:         player.Id,
 			MatchId:        matchRecordReq.MatchId,
-			OpponentId:     matchRecordReq.Players[1-i].Id,
-			OpponentRating: matchRecordReq.Players[1-i].OldRating,
-			OpponentRD:     matchRecordReq.Players[1-i].OldRD,
+			OpponentId:     opponent.Id,
+			OpponentRating: opponent.OldRating,
+			OpponentRD:     opponent.OldRD,
 			Result:         matchRecordReq.Results[i],
+			Color:          entities.TeamColor(player.Team),
 			Timestamp:      matchRecordReq.EndedAt.Format(time.RFC3339),
 		}
 		err = storageClient.PutMatchResult(ctx, playerMatchResult)
@@ -90,6 +125,35 @@ func handler(ctx context.Context, event json.RawMessage) error {
 	return nil
 }
 
+// opposingTeam function    merges the players of the other team into a single opponent
+// whose rating and RD are the team averages and whose id is the team's first player.
+func opposingTeam(
+	players []dtos.PlayerRecordRequest,
+	team int,
+) dtos.PlayerRecordRequest {
+	var (
+		opponent dtos.PlayerRecordRequest
+		count    float64
+	)
+	for _, player := range players {
+		if player.Team == team {
+			continue
+		}
+		if count == 0 {
+			opponent.Id = player.Id
+			opponent.Team = player.Team
+		}
+		opponent.OldRating += player.OldRating
+		opponent.OldRD += player.OldRD
+		count++
+	}
+	if count > 0 {
+		opponent.OldRating /= count
+		opponent.OldRD /= count
+	}
+	return opponent
+}
+
 func main() {
 	lambda.Start(handler)
 }
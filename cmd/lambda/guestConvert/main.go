@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+)
+
+var (
+	storageClient *storage.Client
+	guestSecret   []byte
+
+	ErrNotGuest         = errors.New("not a guest")
+	ErrGuestInMatch     = errors.New("guest is in a match")
+	ErrSameUser         = errors.New("guest and account are the same user")
+	matchResultPageSize = int32(100)
+)
+
+func init() {
+	cfg, _ := config.LoadDefaultConfig(context.TODO())
+	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
+	guestSecret, _ = auth.GuestTokenSecret()
+}
+
+/*
+handler function    merges a guest into the account that just signed up.
+The guest's match results move to the account, and its rating replaces the account's one
+when the account has no game of its own yet. The guest profile and rating are removed afterwards.
+Opponents' results and match records keep referring to the guest id.
+*/
+func handler(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+) (
+	events.APIGatewayProxyResponse,
+	error,
+) {
+	userId := auth.MustAuth(event.RequestContext.Authorizer)
+
+	var req dtos.GuestConvertRequest
+	if err := json.Unmarshal([]byte(event.Body), &req); err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusBadRequest,
+		}, fmt.Errorf("failed to validate request: %w", err)
+	}
+	guestId, err := auth.ParseGuestToken(req.GuestToken, guestSecret)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusUnauthorized,
+		}, fmt.Errorf("failed to parse guest token: %w", err)
+	}
+	if guestId == userId {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusBadRequest,
+		}, ErrSameUser
+	}
+
+	guestProfile, err := storageClient.GetUserProfile(ctx, guestId)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserProfileNotFound) {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusNotFound,
+			}, fmt.Errorf("failed to get guest profile: %w", err)
+		}
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get guest profile: %w", err)
+	}
+	if !guestProfile.Guest {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusConflict,
+		}, ErrNotGuest
+	}
+	guestRatings, err := storageClient.GetUserRatings(ctx, guestId)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get guest rating: %w", err)
+	}
+
+	// A running game would end with results written under the guest id
+	_, err = storageClient.CheckForActiveMatch(ctx, guestId)
+	if err == nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusConflict,
+		}, ErrGuestInMatch
+	}
+	if !errors.Is(err, storage.ErrUserMatchNotFound) &&
+		!errors.Is(err, storage.ErrActiveMatchNotFound) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to check for active match: %w", err)
+	}
+
+	accountResults, _, err := storageClient.FetchMatchResults(ctx, userId, nil, 1)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to fetch match results: %w", err)
+	}
+
+	merged, err := mergeMatchResults(ctx, guestId, userId)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to merge match results: %w", err)
+	}
+
+	resp := dtos.GuestConvertResponse{
+		MergedMatches: merged,
+	}
+	if merged > 0 && len(accountResults) == 0 {
+		for _, guestRating := range guestRatings {
+			err = storageClient.UpdateUserRating(
+				ctx,
+				userId,
+				guestRating.Category,
+				storage.UserRatingUpdateOptions{
+					Rating:     aws.Float64(guestRating.Rating),
+					RD:         aws.Float64(guestRating.RD),
+					Volatility: aws.Float64(guestRating.Volatility),
+					RatedAt:    guestRating.RatedAt,
+				},
+			)
+			if err != nil {
+				return events.APIGatewayProxyResponse{
+					StatusCode: http.StatusInternalServerError,
+				}, fmt.Errorf("failed to update user rating: %w", err)
+			}
+			err = mergeRatingHistory(ctx, guestId, userId, guestRating.Category)
+			if err != nil {
+				return events.APIGatewayProxyResponse{
+					StatusCode: http.StatusInternalServerError,
+				}, fmt.Errorf("failed to merge rating history: %w", err)
+			}
+		}
+		resp.RatingMerged = true
+	}
+
+	for _, guestRating := range guestRatings {
+		err := storageClient.DeleteUserRating(ctx, guestId, guestRating.Category)
+		if err != nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusInternalServerError,
+			}, fmt.Errorf("failed to delete guest rating: %w", err)
+		}
+	}
+	if err := storageClient.DeleteUserProfile(ctx, guestId); err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to delete guest profile: %w", err)
+	}
+
+	userProfile, err := storageClient.GetUserProfile(ctx, userId)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get user profile: %w", err)
+	}
+	userRatings, err := storageClient.GetUserRatings(ctx, userId)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get user rating: %w", err)
+	}
+	resp.User = dtos.UserResponseFromEntities(userProfile, userRatings, true)
+
+	respJson, err := json.Marshal(resp)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Body:       string(respJson),
+	}, nil
+}
+
+// mergeMatchResults function    moves every match result of the guest to the account
+func mergeMatchResults(ctx context.Context, guestId, userId string) (int, error) {
+	var (
+		lastKey map[string]types.AttributeValue
+		merged  int
+	)
+	for {
+		matchResults, nextKey, err := storageClient.FetchMatchResults(
+			ctx,
+			guestId,
+			lastKey,
+			matchResultPageSize,
+		)
+		if err != nil {
+			return merged, fmt.Errorf("failed to fetch match results: %w", err)
+		}
+		for _, matchResult := range matchResults {
+			timestamp := matchResult.Timestamp
+			matchResult.UserId = userId
+			if err := storageClient.PutMatchResult(ctx, matchResult); err != nil {
+				return merged, fmt.Errorf("failed to put match result: %w", err)
+			}
+			if err := storageClient.DeleteMatchResult(ctx, guestId, timestamp); err != nil {
+				return merged, fmt.Errorf("failed to delete match result: %w", err)
+			}
+			merged++
+		}
+		if nextKey == nil {
+			return merged, nil
+		}
+		lastKey = nextKey
+	}
+}
+
+// mergeRatingHistory function    moves the rating history of the guest in a category to the account
+func mergeRatingHistory(ctx context.Context, guestId, userId, category string) error {
+	entries, err := storageClient.FetchRatingHistory(ctx, guestId, category, time.Time{}, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to fetch rating history: %w", err)
+	}
+	for _, entry := range entries {
+		sortKey := entry.SortKey
+		entry.UserId = userId
+		if err := storageClient.PutRatingHistoryEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to put rating history entry: %w", err)
+		}
+		if err := storageClient.DeleteRatingHistoryEntry(ctx, guestId, sortKey); err != nil {
+			return fmt.Errorf("failed to delete rating history entry: %w", err)
+		}
+	}
+	return nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/utils"
+)
+
+// Guest profiles and ratings are removed by the table TTL once unused for this long
+const guestProfileTTL = 30 * 24 * time.Hour
+
+var (
+	storageClient *storage.Client
+	guestSecret   []byte
+)
+
+func init() {
+	cfg, _ := config.LoadDefaultConfig(context.TODO())
+	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
+	guestSecret, _ = auth.GuestTokenSecret()
+}
+
+/*
+handler function    starts or refreshes an anonymous guest session.
+A request carrying a still valid guest token keeps its guest id, profile and rating,
+anything else gets a brand new guest.
+*/
+func handler(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+) (
+	events.APIGatewayProxyResponse,
+	error,
+) {
+	var (
+		userProfile entities.UserProfile
+		userRatings []entities.UserRating
+		found       bool
+	)
+	if guestId, err := auth.GuestAuth(event.Headers, guestSecret); err == nil {
+		userProfile, userRatings, found = getGuest(ctx, guestId)
+	}
+	if !found {
+		userProfile, userRatings = newGuest()
+	}
+
+	// Every session pushes the expiry of the guest further
+	ttl := time.Now().Add(guestProfileTTL).Unix()
+	userProfile.TTL = ttl
+	if err := storageClient.PutUserProfile(ctx, userProfile); err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to put user profile: %w", err)
+	}
+	for i := range userRatings {
+		userRatings[i].TTL = ttl
+		if err := storageClient.PutUserRating(ctx, userRatings[i]); err != nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusInternalServerError,
+			}, fmt.Errorf("failed to put user rating: %w", err)
+		}
+	}
+
+	token, expiresAt, err := auth.SignGuestToken(userProfile.UserId, guestSecret)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to sign guest token: %w", err)
+	}
+	resp := dtos.GuestSessionResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      dtos.UserResponseFromEntities(userProfile, userRatings, true),
+	}
+	respJson, err := json.Marshal(resp)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Body:       string(respJson),
+	}, nil
+}
+
+// getGuest function    loads an existing guest, accounts are never returned
+func getGuest(
+	ctx context.Context,
+	guestId string,
+) (
+	entities.UserProfile,
+	[]entities.UserRating,
+	bool,
+) {
+	userProfile, err := storageClient.GetUserProfile(ctx, guestId)
+	if err != nil || !userProfile.Guest {
+		return entities.UserProfile{}, nil, false
+	}
+	userRatings, err := storageClient.GetUserRatings(ctx, guestId)
+	if err != nil || !userRatings[0].IsGuest() {
+		return entities.UserProfile{}, nil, false
+	}
+	return userProfile, userRatings, true
+}
+
+func newGuest() (entities.UserProfile, []entities.UserRating) {
+	guestId := utils.GenerateUUID()
+	username := fmt.Sprintf("Guest%06d", rand.IntN(1000000))
+	userProfile := entities.UserProfile{
+		UserId:     guestId,
+		Username:   username,
+		Membership: "guest",
+		Guest:      true,
+		CreatedAt:  time.Now(),
+	}
+	return userProfile, entities.InitialUserRatings(guestId, username, true)
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -20,11 +20,13 @@ import (
 	"github.com/chess-vn/slchess/internal/aws/compute"
 	"github.com/chess-vn/slchess/internal/aws/storage"
 	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
 )
 
 var (
 	storageClient *storage.Client
 	computeClient *compute.Client
+	ticketSecret  []byte
 
 	clusterName = os.Getenv("ECS_CLUSTER_NAME")
 	serviceName = os.Getenv("ECS_SERVICE_NAME")
@@ -40,6 +42,7 @@ func init() {
 		ec2.NewFromConfig(cfg),
 		nil,
 	)
+	ticketSecret, _ = auth.MatchTicketSecret()
 }
 
 func handler(
@@ -65,7 +68,7 @@ func handler(
 			StatusCode: http.StatusBadRequest,
 		}, fmt.Errorf("failed to get active match: %w", err)
 	}
-	if activeMatch.Player1.Id != userId && activeMatch.Player2.Id != userId {
+	if !isInMatch(activeMatch, userId) {
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusBadRequest,
 		}, fmt.Errorf("failed to restore match: %w", ErrUserNotInMatch)
@@ -104,6 +107,12 @@ func handler(
 	}
 
 	resp := dtos.ActiveMatchResponseFromEntity(activeMatch)
+	resp.Ticket, err = auth.SignMatchTicket(activeMatch, true, ticketSecret)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to sign match ticket: %w", err)
+	}
 	respJson, err := json.Marshal(resp)
 	if err != nil {
 		return events.APIGatewayProxyResponse{
@@ -117,6 +126,15 @@ func handler(
 	}, nil
 }
 
+func isInMatch(activeMatch entities.ActiveMatch, userId string) bool {
+	for _, player := range activeMatch.Players() {
+		if player.Id == userId {
+			return true
+		}
+	}
+	return false
+}
+
 func main() {
 	lambda.Start(handler)
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewaymanagementapi"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/chess-vn/slchess/internal/app/matchmaker"
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/compute"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+)
+
+const (
+	// The schedule fires every minute, each invocation keeps pairing until the next one
+	invocationPeriod = time.Minute
+	runInterval      = 5 * time.Second
+)
+
+var (
+	mm *matchmaker.Matchmaker
+
+	region            = os.Getenv("AWS_REGION")
+	websocketApiId    = os.Getenv("WEBSOCKET_API_ID")
+	websocketApiStage = os.Getenv("WEBSOCKET_API_STAGE")
+
+	apiEndpoint = fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com/%s", websocketApiId, region, websocketApiStage)
+)
+
+func init() {
+	cfg, _ := config.LoadDefaultConfig(context.Background())
+	ticketSecret, _ := auth.MatchTicketSecret()
+	ratingWindows, err := matchmaker.ParseRatingWindows(os.Getenv("MATCHMAKING_RATING_WINDOWS"))
+	if err != nil {
+		panic(err)
+	}
+	var avoidRepeatFor time.Duration
+	if v := os.Getenv("MATCHMAKING_AVOID_REPEAT_FOR"); v != "" {
+		avoidRepeatFor, err = time.ParseDuration(v)
+		if err != nil {
+			panic(err)
+		}
+	}
+	mm = matchmaker.NewMatchmaker(
+		storage.NewClient(dynamodb.NewFromConfig(cfg)),
+		compute.NewClient(
+			ecs.NewFromConfig(cfg),
+			ec2.NewFromConfig(cfg),
+			nil,
+		),
+		apigatewaymanagementapi.New(apigatewaymanagementapi.Options{
+			BaseEndpoint: aws.String(apiEndpoint),
+			Region:       region,
+			Credentials:  cfg.Credentials,
+		}),
+		matchmaker.Config{
+			ClusterName:            os.Getenv("SERVER_CLUSTER_NAME"),
+			ServiceName:            os.Getenv("SERVER_SERVICE_NAME"),
+			TicketSecret:           ticketSecret,
+			RatingWindows:          ratingWindows,
+			AvoidRecentOpponentFor: avoidRepeatFor,
+			RepeatPenalty:          matchmaker.DefaultRepeatPenalty,
+		},
+	)
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) error {
+	stopAt := time.Now().Add(invocationPeriod - runInterval)
+	// A failing run does not stop the next ones, the invocation reports the last failure
+	var runErr error
+	for {
+		created, err := mm.Run(ctx)
+		if err != nil {
+			runErr = fmt.Errorf("failed to run matchmaker: %w", err)
+		}
+		if created > 0 {
+			log.Printf("created %d matches", created)
+		}
+		if time.Now().Add(runInterval).After(stopAt) {
+			return runErr
+		}
+		select {
+		case <-ctx.Done():
+			return runErr
+		case <-time.After(runInterval):
+		}
+	}
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -13,11 +13,15 @@ import (
 	"github.com/chess-vn/slchess/internal/aws/storage"
 )
 
-var storageClient *storage.Client
+var (
+	storageClient *storage.Client
+	guestSecret   []byte
+)
 
 func init() {
 	cfg, _ := config.LoadDefaultConfig(context.TODO())
 	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
+	guestSecret, _ = auth.GuestTokenSecret()
 }
 
 func handler(
@@ -27,9 +31,18 @@ func handler(
 	events.APIGatewayProxyResponse,
 	error,
 ) {
-	userId := auth.MustAuth(event.RequestContext.Authorizer)
+	userId, _, err := auth.Identify(
+		event.RequestContext.Authorizer,
+		event.Headers,
+		guestSecret,
+	)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusUnauthorized,
+		}, fmt.Errorf("failed to authenticate: %w", err)
+	}
 
-	err := storageClient.DeleteMatchmakingTicket(ctx, userId)
+	err = storageClient.DeleteMatchmakingTicket(ctx, userId)
 	if err != nil {
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusInternalServerError,
@@ -41,6 +41,9 @@ var (
 	ErrInvalidGameMode        = errors.New("invalid game mode")
 	ErrServertestNotAvailable = errors.New("servertest not available")
 
+	testRating = 1200.0
+	testRD     = 200.0
+
 	timeLayout  = "2006-01-02 15:04:05.999999999 -0700 MST"
 	apiEndpoint = fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com/%s", websocketApiId, region, websocketApiStage)
 )
@@ -81,10 +84,12 @@ func handler(
 	userRating := entities.UserRating{
 		UserId:       userId,
 		PartitionKey: "UserRatings",
-		Rating:       1200,
-		RD:           200,
+		Rating:       testRating,
+		RD:           testRD,
 	}
 	ticket := dtos.MatchmakingRequestToEntity(userRating, matchmakingReq)
+	ticket.EnqueuedAt = time.Now()
+	ticket.Refresh(ticket.EnqueuedAt)
 	if err := ticket.Validate(); err != nil {
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusBadRequest,
@@ -185,20 +190,20 @@ func findOpponents(
 	[]string,
 	error,
 ) {
-	tickets, err := storageClient.ScanMatchmakingTickets(ctx, ticket)
+	tickets, err := storageClient.FetchMatchmakingTickets(ctx, ticket.GameMode, ticket.Pool)
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan matchmaking tickets: %w", err)
+		return nil, fmt.Errorf("failed to fetch matchmaking tickets: %w", err)
 	}
 
+	// Tickets that missed their heartbeats belong to load test clients that are gone
 	var opponentIds []string
-	if len(tickets) > 0 {
-		for _, opTicket := range tickets {
-			if opTicket.UserId == ticket.UserId {
-				continue
-			}
-			opponentIds = append(opponentIds, opTicket.UserId)
+	for _, opTicket := range tickets {
+		if opTicket.UserId == ticket.UserId || opTicket.Expired(ticket.EnqueuedAt) {
+			continue
 		}
-	} else {
+		opponentIds = append(opponentIds, opTicket.UserId)
+	}
+	if len(opponentIds) == 0 {
 		// No match found, add the user ticket to the queue
 		storageClient.PutMatchmakingTickets(ctx, ticket)
 	}
@@ -220,8 +225,12 @@ func createMatch(
 		MatchId:        utils.GenerateUUID(),
 		ConversationId: utils.GenerateUUID(),
 		PartitionKey:   "ActiveMatches",
+		Player1:        testPlayer(opponentId, 0),
+		Player2:        testPlayer(userId, 1),
+		Casual:         true,
 		GameMode:       gameMode,
 		Server:         serverIp,
+		AverageRating:  testRating,
 		CreatedAt:      time.Now(),
 	}
 
@@ -257,6 +266,19 @@ func createMatch(
 	return match, nil
 }
 
+// testPlayer function    returns a load test player, every one of them has the same rating
+func testPlayer(userId string, team int) entities.Player {
+	return entities.Player{
+		Id:         userId,
+		Username:   userId,
+		Rating:     testRating,
+		RD:         testRD,
+		NewRatings: []float64{testRating, testRating, testRating},
+		NewRDs:     []float64{testRD, testRD, testRD},
+		Team:       team,
+	}
+}
+
 func notifyQueueingUser(ctx context.Context, userId string, data []byte) error {
 	// Get user ID from DynamoDB
 	connection, err := storageClient.GetConnectionByUserId(ctx, userId)
@@ -50,16 +50,12 @@ func handler(
 		return event, fmt.Errorf("failed to put puzzle profile: %w", err)
 	}
 
-	// Default user rating
-	err = storageClient.PutUserRating(ctx, entities.UserRating{
-		UserId:       userId,
-		Username:     username,
-		Rating:       1200.0,
-		RD:           100.0,
-		PartitionKey: "UserRatings",
-	})
-	if err != nil {
-		return event, fmt.Errorf("failed to put user rating: %w", err)
+	// Default user rating in every category
+	for _, userRating := range entities.InitialUserRatings(userId, username, false) {
+		err = storageClient.PutUserRating(ctx, userRating)
+		if err != nil {
+			return event, fmt.Errorf("failed to put user rating: %w", err)
+		}
 	}
 
 	return event, nil
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -14,12 +15,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/apigatewaymanagementapi"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/chess-vn/slchess/internal/app/matchmaker"
 	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/entities"
 )
 
 var (
 	apigatewayClient *apigatewaymanagementapi.Client
 	storageClient    *storage.Client
+	ratingWindows    map[string]matchmaker.RatingWindow
 
 	region            = os.Getenv("AWS_REGION")
 	websocketApiId    = os.Getenv("WEBSOCKET_API_ID")
@@ -40,8 +44,18 @@ func init() {
 		Region:       region,
 		Credentials:  cfg.Credentials,
 	})
+	var err error
+	ratingWindows, err = matchmaker.ParseRatingWindows(os.Getenv("MATCHMAKING_RATING_WINDOWS"))
+	if err != nil {
+		panic(err)
+	}
 }
 
+/*
+handler function    is the heartbeat of a queueing client.
+A player already in a match gets the match pushed back, a queued player gets the ticket refreshed
+and the queue status of its game mode pushed instead.
+*/
 func handler(
 	ctx context.Context,
 	event events.APIGatewayWebsocketProxyRequest,
@@ -63,9 +77,7 @@ func handler(
 	if err != nil {
 		if errors.Is(err, storage.ErrUserMatchNotFound) ||
 			errors.Is(err, storage.ErrActiveMatchNotFound) {
-			return events.APIGatewayProxyResponse{
-				StatusCode: http.StatusOK,
-			}, nil
+			return pushQueueStatus(ctx, connection)
 		}
 		return events.APIGatewayProxyResponse{
 			StatusCode: http.StatusInternalServerError,
@@ -96,6 +108,64 @@ func handler(
 	}, nil
 }
 
+// pushQueueStatus function    refreshes the user's ticket and pushes its queue status
+func pushQueueStatus(
+	ctx context.Context,
+	connection entities.Connection,
+) (
+	events.APIGatewayProxyResponse,
+	error,
+) {
+	now := time.Now()
+	ticket, err := storageClient.RefreshMatchmakingTicket(ctx, connection.UserId, now)
+	if err != nil {
+		if errors.Is(err, storage.ErrMatchmakingTicketNotFound) {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusOK,
+			}, nil
+		}
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to refresh matchmaking ticket: %w", err)
+	}
+
+	queue, err := storageClient.FetchMatchmakingTickets(ctx, ticket.GameMode, ticket.Pool)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to fetch matchmaking tickets: %w", err)
+	}
+	status := matchmaker.QueueStatus(
+		ticket,
+		queue,
+		matchmaker.RatingWindowOf(ratingWindows, ticket.GameMode),
+		now,
+	)
+	statusJson, err := json.Marshal(status)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	_, err = apigatewayClient.PostToConnection(
+		ctx,
+		&apigatewaymanagementapi.PostToConnectionInput{
+			ConnectionId: &connection.Id,
+			Data:         statusJson,
+		},
+	)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to post to connection: %w", err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
 func main() {
 	lambda.Start(handler)
 }
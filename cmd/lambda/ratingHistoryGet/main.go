@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+const (
+	dateLayout = time.DateOnly
+	// Range of the points when the request has no from date
+	defaultRange = 365 * 24 * time.Hour
+)
+
+var storageClient *storage.Client
+
+func init() {
+	cfg, _ := config.LoadDefaultConfig(context.TODO())
+	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
+}
+
+/*
+handler function    returns a user's rating over time in one category, one point per day with games.
+Peak rating and the change this month cover the whole history, whatever the requested range.
+*/
+func handler(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+) (
+	events.APIGatewayProxyResponse,
+	error,
+) {
+	userId := auth.MustAuth(event.RequestContext.Authorizer)
+	now := time.Now()
+	targetId, category, from, to, err := extractParameters(
+		userId,
+		event.QueryStringParameters,
+		now,
+	)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusBadRequest,
+		}, fmt.Errorf("failed to extract parameters: %w", err)
+	}
+
+	entries, err := storageClient.FetchRatingHistory(ctx, targetId, category, time.Time{}, now)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to fetch rating history: %w", err)
+	}
+
+	resp := dtos.RatingHistoryResponse{
+		UserId:      targetId,
+		Category:    category,
+		Points:      dailyPoints(entries, from, to),
+		Peak:        peakRating(entries),
+		MonthChange: monthChange(entries, now),
+	}
+	respJson, err := json.Marshal(resp)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Body:       string(respJson),
+	}, nil
+}
+
+func extractParameters(
+	userId string,
+	params map[string]string,
+	now time.Time,
+) (
+	string,
+	string,
+	time.Time,
+	time.Time,
+	error,
+) {
+	targetId := userId
+	if userIdStr, ok := params["userId"]; ok {
+		targetId = userIdStr
+	}
+
+	category := entities.RatingCategoryRapid
+	if categoryStr, ok := params["category"]; ok {
+		if err := entities.ValidateRatingCategory(categoryStr); err != nil {
+			return "", "", time.Time{}, time.Time{}, err
+		}
+		category = categoryStr
+	}
+
+	// The to date is inclusive
+	to := now.UTC()
+	if toStr, ok := params["to"]; ok {
+		date, err := time.Parse(dateLayout, toStr)
+		if err != nil {
+			return "", "", time.Time{}, time.Time{}, fmt.Errorf("invalid to date: %w", err)
+		}
+		to = date.Add(24*time.Hour - time.Second)
+	}
+	from := to.Add(-defaultRange)
+	if fromStr, ok := params["from"]; ok {
+		date, err := time.Parse(dateLayout, fromStr)
+		if err != nil {
+			return "", "", time.Time{}, time.Time{}, fmt.Errorf("invalid from date: %w", err)
+		}
+		from = date
+	}
+	if from.After(to) {
+		return "", "", time.Time{}, time.Time{}, fmt.Errorf("from date after to date")
+	}
+
+	return targetId, category, from, to, nil
+}
+
+// dailyPoints function    aggregates the entries rated between from and to into one point per UTC day
+func dailyPoints(
+	entries []entities.RatingHistoryEntry,
+	from time.Time,
+	to time.Time,
+) []dtos.RatingHistoryPointResponse {
+	points := []dtos.RatingHistoryPointResponse{}
+	for _, entry := range entries {
+		if entry.RatedAt.Before(from) || entry.RatedAt.After(to) {
+			continue
+		}
+		date := entry.RatedAt.UTC().Format(dateLayout)
+		if len(points) == 0 || points[len(points)-1].Date != date {
+			points = append(points, dtos.RatingHistoryPointResponse{
+				Date: date,
+				High: entry.Rating,
+				Low:  entry.Rating,
+			})
+		}
+		point := &points[len(points)-1]
+		point.Rating = entry.Rating
+		point.High = max(point.High, entry.Rating)
+		point.Low = min(point.Low, entry.Rating)
+		point.Games++
+	}
+	return points
+}
+
+// peakRating function    returns the highest rating ever reached and the day it was reached first
+func peakRating(entries []entities.RatingHistoryEntry) *dtos.RatingPeakResponse {
+	var peak *dtos.RatingPeakResponse
+	for _, entry := range entries {
+		if peak != nil && entry.Rating <= peak.Rating {
+			continue
+		}
+		peak = &dtos.RatingPeakResponse{
+			Rating: entry.Rating,
+			Date:   entry.RatedAt.UTC().Format(dateLayout),
+		}
+	}
+	return peak
+}
+
+// monthChange function    returns the rating gained between the start of the month of now and now
+func monthChange(entries []entities.RatingHistoryEntry, now time.Time) float64 {
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		base    float64
+		hasBase bool
+		current float64
+	)
+	for _, entry := range entries {
+		if entry.RatedAt.Before(monthStart) {
+			base, hasBase = entry.Rating, true
+			continue
+		}
+		// First game of a user who started playing this month
+		if !hasBase {
+			base, hasBase = entry.OldRating, true
+		}
+		current = entry.Rating
+	}
+	if current == 0 {
+		return 0
+	}
+	return current - base
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/compute"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/utils"
+)
+
+var (
+	storageClient *storage.Client
+	computeClient *compute.Client
+
+	clusterName = os.Getenv("SERVER_CLUSTER_NAME")
+	serviceName = os.Getenv("SERVER_SERVICE_NAME")
+
+	ErrNotSimulHost    = errors.New("user is not the simul host")
+	ErrSimulNotOpen    = errors.New("simul is not open")
+	ErrNoParticipants  = errors.New("simul has no participants")
+	ErrUserInOtherGame = errors.New("participant already in a match")
+	ErrNoBoards        = errors.New("no simul board could be created")
+)
+
+func init() {
+	cfg, _ := config.LoadDefaultConfig(context.TODO())
+	storageClient = storage.NewClient(dynamodb.NewFromConfig(cfg))
+	computeClient = compute.NewClient(
+		ecs.NewFromConfig(cfg),
+		ec2.NewFromConfig(cfg),
+		nil,
+	)
+}
+
+func handler(
+	ctx context.Context,
+	event events.APIGatewayProxyRequest,
+) (
+	events.APIGatewayProxyResponse,
+	error,
+) {
+	userId := auth.MustAuth(event.RequestContext.Authorizer)
+	simulId := event.PathParameters["id"]
+
+	simul, err := storageClient.GetSimul(ctx, simulId)
+	if err != nil {
+		if errors.Is(err, storage.ErrSimulNotFound) {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusNotFound,
+			}, nil
+		}
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get simul: %w", err)
+	}
+	if simul.HostId != userId {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusForbidden,
+		}, ErrNotSimulHost
+	}
+	if simul.Status != entities.SimulStatusOpen {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusConflict,
+		}, ErrSimulNotOpen
+	}
+	if len(simul.Participants) == 0 {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusBadRequest,
+		}, ErrNoParticipants
+	}
+
+	// Every board of a simul must live on the same server as the host socket
+	var (
+		serverIp     string
+		pendingCount int
+	)
+	for range 5 {
+		serverIp, pendingCount, err = computeClient.GetAvailableServerIp(ctx, clusterName, serviceName)
+		if err == nil {
+			break
+		}
+		if err == compute.ErrNoServerAvailable && pendingCount == 0 {
+			computeClient.StartNewTask(ctx, clusterName, serviceName)
+		}
+		time.Sleep(5 + time.Duration(rand.IntN(5))*time.Second)
+	}
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get server ip: %w", err)
+	}
+
+	category, err := entities.RatingCategoryOf(simul.GameMode)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get rating category: %w", err)
+	}
+	hostRating, err := storageClient.GetUserRating(ctx, simul.HostId, category)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to get host rating: %w", err)
+	}
+
+	matchIds := make([]string, 0, len(simul.Participants))
+	for _, participantId := range simul.Participants {
+		match, err := createBoard(ctx, simul, hostRating, participantId, serverIp)
+		if err != nil {
+			// A participant who is busy elsewhere simply gets no board
+			continue
+		}
+		matchIds = append(matchIds, match.MatchId)
+	}
+	if len(matchIds) == 0 {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusConflict,
+		}, ErrNoBoards
+	}
+
+	now := time.Now()
+	simul.Status = entities.SimulStatusStarted
+	simul.Server = serverIp
+	simul.MatchIds = matchIds
+	simul.StartedAt = &now
+	err = storageClient.UpdateSimul(ctx, simulId, storage.SimulUpdateOptions{
+		Status:    aws.String(simul.Status),
+		Server:    aws.String(simul.Server),
+		MatchIds:  simul.MatchIds,
+		StartedAt: simul.StartedAt,
+	})
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to update simul: %w", err)
+	}
+
+	respJson, err := json.Marshal(dtos.SimulResponseFromEntity(simul))
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusInternalServerError,
+		}, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Body:       string(respJson),
+	}, nil
+}
+
+/*
+createBoard function    creates the active match between the host and one participant.
+Simul games are unrated, so every precomputed outcome keeps the current rating.
+The host plays white on every board.
+*/
+func createBoard(
+	ctx context.Context,
+	simul entities.Simul,
+	hostRating entities.UserRating,
+	participantId string,
+	serverIp string,
+) (
+	entities.ActiveMatch,
+	error,
+) {
+	participantRating, err := storageClient.GetUserRating(ctx, participantId, hostRating.Category)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to get user rating: %w", err)
+	}
+
+	match := entities.ActiveMatch{
+		MatchId:        utils.GenerateUUID(),
+		ConversationId: utils.GenerateUUID(),
+		PartitionKey:   "ActiveMatches",
+		Player1:        unratedPlayer(hostRating),
+		Player2:        unratedPlayer(participantRating),
+		SimulId:        simul.SimulId,
+		GameMode:       simul.GameMode,
+		Server:         serverIp,
+		CreatedAt:      time.Now(),
+	}
+	match.Player2.Team = 1
+	match.AverageRating = (match.Player1.Rating + match.Player2.Rating) / 2
+
+	// The host plays many boards at once, so only the participant is bound to the match
+	err = storageClient.PutUserMatch(ctx, entities.UserMatch{
+		UserId:  participantId,
+		MatchId: match.MatchId,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrUserMatchAlreadyExisted) {
+			return entities.ActiveMatch{}, fmt.Errorf("%w: %w", ErrUserInOtherGame, err)
+		}
+		return entities.ActiveMatch{}, fmt.Errorf("failed to put user match: %w", err)
+	}
+
+	if err := storageClient.PutActiveMatch(ctx, match); err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to put active match: %w", err)
+	}
+
+	err = storageClient.PutSpectatorConversation(
+		ctx,
+		entities.SpectatorConversation{
+			MatchId:        match.MatchId,
+			ConversationId: utils.GenerateUUID(),
+		},
+	)
+	if err != nil {
+		return entities.ActiveMatch{}, err
+	}
+
+	return match, nil
+}
+
+func unratedPlayer(userRating entities.UserRating) entities.Player {
+	return entities.Player{
+		Id:         userRating.UserId,
+		Username:   userRating.Username,
+		Rating:     userRating.Rating,
+		RD:         userRating.RD,
+		NewRatings: []float64{userRating.Rating, userRating.Rating, userRating.Rating},
+		NewRDs:     []float64{userRating.RD, userRating.RD, userRating.RD},
+	}
+}
+
+func main() {
+	lambda.Start(handler)
+}
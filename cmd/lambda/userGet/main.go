@@ -48,7 +48,7 @@ func handler(
 		}, fmt.Errorf("failed to get user profile: %w", err)
 	}
 
-	userRating, err := storageClient.GetUserRating(ctx, targetId)
+	userRatings, err := storageClient.GetUserRatings(ctx, targetId)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserRatingNotFound) {
 			return events.APIGatewayProxyResponse{
@@ -65,7 +65,7 @@ func handler(
 	if userId == targetId {
 		getFull = true
 	}
-	user := dtos.UserResponseFromEntities(userProfile, userRating, getFull)
+	user := dtos.UserResponseFromEntities(userProfile, userRatings, getFull)
 	userJson, err := json.Marshal(user)
 	if err != nil {
 		return events.APIGatewayProxyResponse{
@@ -15,6 +15,7 @@ import (
 	"github.com/chess-vn/slchess/internal/aws/auth"
 	"github.com/chess-vn/slchess/internal/aws/storage"
 	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
 )
 
 var storageClient *storage.Client
@@ -38,8 +39,18 @@ func handler(
 			StatusCode: http.StatusBadRequest,
 		}, fmt.Errorf("failed to extract parameters: %w", err)
 	}
+	category := event.QueryStringParameters["category"]
+	if category == "" {
+		category = entities.RatingCategoryRapid
+	}
+	if err := entities.ValidateRatingCategory(category); err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusBadRequest,
+		}, fmt.Errorf("invalid category: %w", err)
+	}
 	userRatings, lastEvalKey, err := storageClient.FetchUserRatings(
 		ctx,
+		category,
 		startKey,
 		limit,
 	)
@@ -49,11 +60,15 @@ func handler(
 		}, fmt.Errorf("failed to fetch user ratings: %w", err)
 	}
 
-	resp := dtos.UserRatingListResponseFromEntities(userRatings)
+	resp := dtos.UserRatingListResponseFromEntities(category, userRatings)
 	if lastEvalKey != nil {
-		resp.NextPageToken = &dtos.NextUserRatingPageToken{
-			Rating: lastEvalKey["Rating"].(*types.AttributeValueMemberS).Value,
+		nextPageToken, err := nextPageTokenOf(lastEvalKey)
+		if err != nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusInternalServerError,
+			}, fmt.Errorf("failed to build next page token: %w", err)
 		}
+		resp.NextPageToken = &nextPageToken
 	}
 
 	respJson, err := json.Marshal(resp)
@@ -91,7 +106,16 @@ func extractScanParameters(params map[string]string) (
 			return nil, 0, err
 		}
 		startKey = map[string]types.AttributeValue{
-			"Rating": &types.AttributeValueMemberS{
+			"UserId": &types.AttributeValueMemberS{
+				Value: nextPageToken.UserId,
+			},
+			"Category": &types.AttributeValueMemberS{
+				Value: nextPageToken.Category,
+			},
+			"PartitionKey": &types.AttributeValueMemberS{
+				Value: nextPageToken.PartitionKey,
+			},
+			"Rating": &types.AttributeValueMemberN{
 				Value: nextPageToken.Rating,
 			},
 		}
@@ -100,6 +124,28 @@ func extractScanParameters(params map[string]string) (
 	return startKey, limit, nil
 }
 
+// nextPageTokenOf function    encodes the base table and rating index keys of the last evaluated item
+func nextPageTokenOf(lastEvalKey map[string]types.AttributeValue) (dtos.NextUserRatingPageToken, error) {
+	var nextPageToken dtos.NextUserRatingPageToken
+	for name, value := range map[string]*string{
+		"UserId":       &nextPageToken.UserId,
+		"Category":     &nextPageToken.Category,
+		"PartitionKey": &nextPageToken.PartitionKey,
+	} {
+		member, ok := lastEvalKey[name].(*types.AttributeValueMemberS)
+		if !ok {
+			return dtos.NextUserRatingPageToken{}, fmt.Errorf("missing key attribute: %s", name)
+		}
+		*value = member.Value
+	}
+	rating, ok := lastEvalKey["Rating"].(*types.AttributeValueMemberN)
+	if !ok {
+		return dtos.NextUserRatingPageToken{}, fmt.Errorf("missing key attribute: Rating")
+	}
+	nextPageToken.Rating = rating.Value
+	return nextPageToken, nil
+}
+
 func main() {
 	lambda.Start(handler)
 }
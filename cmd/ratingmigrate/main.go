@@ -0,0 +1,98 @@
+/*
+ratingmigrate copies the single rating of every user from the legacy UserRatings table
+into one rating per category in the current user ratings table.
+Every category starts from the legacy rating, ratings already present are left untouched
+so the migration can run again after a partial failure.
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"go.uber.org/zap"
+)
+
+var legacyTableName = os.Getenv("LEGACY_USER_RATINGS_TABLE_NAME")
+
+func main() {
+	ctx := context.Background()
+	if legacyTableName == "" {
+		logging.Fatal("missing LEGACY_USER_RATINGS_TABLE_NAME")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		logging.Fatal("failed to load aws config", zap.Error(err))
+	}
+	dynamoClient := dynamodb.NewFromConfig(cfg)
+	storageClient := storage.NewClient(dynamoClient)
+
+	var (
+		lastKey  map[string]types.AttributeValue
+		migrated int
+	)
+	for {
+		output, err := dynamoClient.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(legacyTableName),
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			logging.Fatal("failed to scan legacy user ratings", zap.Error(err))
+		}
+		var legacyRatings []entities.UserRating
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &legacyRatings); err != nil {
+			logging.Fatal("failed to unmarshal legacy user ratings", zap.Error(err))
+		}
+		for _, legacyRating := range legacyRatings {
+			if err := migrate(ctx, storageClient, legacyRating); err != nil {
+				logging.Fatal(
+					"failed to migrate user rating",
+					zap.String("user_id", legacyRating.UserId),
+					zap.Error(err),
+				)
+			}
+			migrated++
+		}
+		if output.LastEvaluatedKey == nil {
+			break
+		}
+		lastKey = output.LastEvaluatedKey
+	}
+	logging.Info("user ratings migrated", zap.Int("users", migrated))
+}
+
+// migrate function    writes the legacy rating of a user into every category it has no rating in yet
+func migrate(
+	ctx context.Context,
+	storageClient *storage.Client,
+	legacyRating entities.UserRating,
+) error {
+	for _, category := range entities.RatingCategories() {
+		_, err := storageClient.GetUserRating(ctx, legacyRating.UserId, category)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, storage.ErrUserRatingNotFound) {
+			return fmt.Errorf("failed to get user rating: %w", err)
+		}
+
+		userRating := legacyRating
+		userRating.Category = category
+		userRating.PartitionKey = entities.RatingsPartitionKey(category, legacyRating.IsGuest())
+		if err := storageClient.PutUserRating(ctx, userRating); err != nil {
+			return fmt.Errorf("failed to put user rating: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+/*
+ratingreplay recomputes every user rating by replaying all match records in the order they ended
+through the rating package, then rewrites the user ratings and their rating history.
+
+With -dry-run nothing is written and a report of the rating changes is printed instead.
+Table names come from the same environment variables as the lambdas, -endpoint points the
+tool at a local DynamoDB.
+*/
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/app/ratingreplay"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"github.com/chess-vn/slchess/pkg/rating"
+	"go.uber.org/zap"
+)
+
+var (
+	dryRun   = flag.Bool("dry-run", false, "print the rating changes without writing them")
+	endpoint = flag.String("endpoint", "", "DynamoDB endpoint, e.g. http://localhost:8000 for a local DynamoDB")
+)
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		logging.Fatal("failed to load aws config", zap.Error(err))
+	}
+	storageClient := storage.NewClient(dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if *endpoint != "" {
+			o.BaseEndpoint = aws.String(*endpoint)
+		}
+	}))
+
+	matchRecords, err := scanMatchRecords(ctx, storageClient)
+	if err != nil {
+		logging.Fatal("failed to scan match records", zap.Error(err))
+	}
+	ratingreplay.SortMatchRecords(matchRecords)
+
+	userRatings, err := getUserRatings(ctx, storageClient, matchRecords)
+	if err != nil {
+		logging.Fatal("failed to get user ratings", zap.Error(err))
+	}
+
+	// Guests and users without a rating keep the rating recorded in the match records
+	replayer := ratingreplay.NewReplayer(rating.DefaultConfig, func(key ratingreplay.Key) bool {
+		userRating, ok := userRatings[key]
+		return ok && !userRating.IsGuest()
+	})
+	var replayed, skipped int
+	for _, matchRecord := range matchRecords {
+		if err := replayer.Replay(matchRecord); err != nil {
+			logging.Warn(
+				"match record skipped",
+				zap.String("match_id", matchRecord.MatchId),
+				zap.Error(err),
+			)
+			skipped++
+			continue
+		}
+		replayed++
+	}
+	states := replayer.States()
+	logging.Info(
+		"match records replayed",
+		zap.Int("replayed", replayed),
+		zap.Int("skipped", skipped),
+		zap.Int("ratings", len(states)),
+	)
+
+	if *dryRun {
+		printReport(userRatings, states)
+		return
+	}
+	for key, state := range states {
+		if err := write(ctx, storageClient, key, state); err != nil {
+			logging.Fatal(
+				"failed to write replayed rating",
+				zap.String("user_id", key.UserId),
+				zap.String("category", key.Category),
+				zap.Error(err),
+			)
+		}
+	}
+	logging.Info("replayed ratings written", zap.Int("ratings", len(states)))
+}
+
+func scanMatchRecords(
+	ctx context.Context,
+	storageClient *storage.Client,
+) (
+	[]entities.MatchRecord,
+	error,
+) {
+	var (
+		matchRecords []entities.MatchRecord
+		lastKey      map[string]types.AttributeValue
+	)
+	for {
+		page, nextKey, err := storageClient.ScanMatchRecords(ctx, lastKey)
+		if err != nil {
+			return nil, err
+		}
+		matchRecords = append(matchRecords, page...)
+		if nextKey == nil {
+			return matchRecords, nil
+		}
+		lastKey = nextKey
+	}
+}
+
+// getUserRatings function    returns the stored rating of every player of the match records in the categories they played
+func getUserRatings(
+	ctx context.Context,
+	storageClient *storage.Client,
+	matchRecords []entities.MatchRecord,
+) (
+	map[ratingreplay.Key]entities.UserRating,
+	error,
+) {
+	userRatings := make(map[ratingreplay.Key]entities.UserRating)
+	fetched := make(map[ratingreplay.Key]bool)
+	for _, matchRecord := range matchRecords {
+		category, err := entities.RatingCategoryOf(matchRecord.GameMode)
+		if err != nil {
+			continue
+		}
+		for _, player := range matchRecord.Players {
+			key := ratingreplay.Key{UserId: player.Id, Category: category}
+			if fetched[key] {
+				continue
+			}
+			fetched[key] = true
+			userRating, err := storageClient.GetUserRating(ctx, key.UserId, key.Category)
+			if err != nil {
+				if errors.Is(err, storage.ErrUserRatingNotFound) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get user rating: [userId: %s] - %w", key.UserId, err)
+			}
+			userRatings[key] = userRating
+		}
+	}
+	return userRatings, nil
+}
+
+// write function    stores a replayed rating and replaces the rating history of its category
+func write(
+	ctx context.Context,
+	storageClient *storage.Client,
+	key ratingreplay.Key,
+	state ratingreplay.State,
+) error {
+	err := storageClient.UpdateUserRating(ctx, key.UserId, key.Category, storage.UserRatingUpdateOptions{
+		Rating:     aws.Float64(state.Rating.Rating),
+		RD:         aws.Float64(state.Rating.RD),
+		Volatility: aws.Float64(state.Rating.Volatility),
+		RatedAt:    aws.Time(state.RatedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update user rating: %w", err)
+	}
+
+	entries, err := storageClient.FetchRatingHistory(
+		ctx,
+		key.UserId,
+		key.Category,
+		time.Time{},
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch rating history: %w", err)
+	}
+	replayed := make(map[string]bool, len(state.History))
+	for _, entry := range state.History {
+		replayed[entry.SortKey] = true
+	}
+	for _, entry := range entries {
+		if replayed[entry.SortKey] {
+			continue
+		}
+		err := storageClient.DeleteRatingHistoryEntry(ctx, key.UserId, entry.SortKey)
+		if err != nil {
+			return fmt.Errorf("failed to delete rating history entry: %w", err)
+		}
+	}
+	for _, entry := range state.History {
+		if err := storageClient.PutRatingHistoryEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to put rating history entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// printReport function    prints the replayed ratings that differ from the stored ones, largest change first
+func printReport(
+	userRatings map[ratingreplay.Key]entities.UserRating,
+	states map[ratingreplay.Key]ratingreplay.State,
+) {
+	type change struct {
+		key    ratingreplay.Key
+		stored entities.UserRating
+		state  ratingreplay.State
+		delta  float64
+	}
+	changes := make([]change, 0, len(states))
+	for key, state := range states {
+		stored := userRatings[key]
+		delta := state.Rating.Rating - stored.Rating
+		if math.Abs(delta) < 0.01 && math.Abs(state.Rating.RD-stored.RD) < 0.01 {
+			continue
+		}
+		changes = append(changes, change{key, stored, state, delta})
+	}
+	slices.SortFunc(changes, func(a, b change) int {
+		return cmp.Compare(math.Abs(b.delta), math.Abs(a.delta))
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "user\tusername\tcategory\tgames\trating\treplayed\tchange\tRD\treplayed RD\t")
+	for _, c := range changes {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%d\t%.2f\t%.2f\t%+.2f\t%.2f\t%.2f\t\n",
+			c.key.UserId,
+			c.stored.Username,
+			c.key.Category,
+			len(c.state.History),
+			c.stored.Rating,
+			c.state.Rating.Rating,
+			c.delta,
+			c.stored.RD,
+			c.state.Rating.RD,
+		)
+	}
+	w.Flush()
+	fmt.Printf("%d of %d replayed ratings differ from the stored ones\n", len(changes), len(states))
+}
@@ -0,0 +1,179 @@
+package adjourned
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/pgn"
+	"github.com/notnil/chess"
+)
+
+var ErrNotExpired = errors.New("adjournment not expired")
+
+// Index of the precomputed draw rating, after the win and before the loss
+const drawIdx = 1
+
+// Expired function    reports whether nobody resumed the adjourned match before its deadline
+func Expired(adjournment *entities.Adjournment, now time.Time) bool {
+	return adjournment != nil && now.After(adjournment.Deadline)
+}
+
+// Clocks function    parses the clock after every saved move and the clock of each team, unreadable move clocks stay zero
+func Clocks(adjournment entities.Adjournment) ([]time.Duration, []time.Duration, error) {
+	moveClocks := make([]time.Duration, len(adjournment.Moves))
+	for i, clock := range adjournment.MoveClocks {
+		if d, err := time.ParseDuration(clock); err == nil && i < len(moveClocks) {
+			moveClocks[i] = d
+		}
+	}
+	clocks := make([]time.Duration, 0, len(adjournment.Clocks))
+	for _, clock := range adjournment.Clocks {
+		d, err := time.ParseDuration(clock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse clock: %w", err)
+		}
+		clocks = append(clocks, d)
+	}
+	return moveClocks, clocks, nil
+}
+
+/*
+Record function    returns the end game request of an adjourned match nobody resumed before its deadline.
+The match is scored as a draw the same way the server scores it when a player comes back too late.
+*/
+func Record(activeMatch entities.ActiveMatch) (dtos.MatchRecordRequest, error) {
+	adjournment := activeMatch.Adjournment
+	if !Expired(adjournment, time.Now()) {
+		return dtos.MatchRecordRequest{}, ErrNotExpired
+	}
+	gameMode, err := entities.ParseGameMode(activeMatch.GameMode)
+	if err != nil {
+		return dtos.MatchRecordRequest{}, fmt.Errorf("failed to parse game mode: %w", err)
+	}
+	moveClocks, _, err := Clocks(*adjournment)
+	if err != nil {
+		return dtos.MatchRecordRequest{}, err
+	}
+	game := chess.NewGame(chess.UseNotation(chess.UCINotation{}))
+	for _, uci := range adjournment.Moves {
+		if err := game.MoveStr(uci); err != nil {
+			return dtos.MatchRecordRequest{}, fmt.Errorf("failed to restore game: %w", err)
+		}
+	}
+	// The simul session is not loaded here, its boards are unrated like casual games
+	casual := activeMatch.Casual || activeMatch.SimulId != ""
+	players := activeMatch.Players()
+
+	matchRecordReq := dtos.MatchRecordRequest{
+		MatchId:  activeMatch.MatchId,
+		GameMode: activeMatch.GameMode,
+		Players:  make([]dtos.PlayerRecordRequest, 0, len(players)),
+		Plies:    make([]dtos.PlyRecordRequest, 0, len(adjournment.Moves)),
+		EndedAt:  adjournment.Deadline,
+		Results:  make([]float64, 0, len(players)),
+		Casual:   casual,
+	}
+	if activeMatch.StartedAt != nil {
+		matchRecordReq.StartedAt = *activeMatch.StartedAt
+	}
+	for _, player := range players {
+		if len(player.NewRatings) <= drawIdx || len(player.NewRDs) <= drawIdx {
+			return dtos.MatchRecordRequest{}, fmt.Errorf("missing draw rating of player %s", player.Id)
+		}
+		// Unrated players come without precomputed volatilities
+		volatility := player.Volatility
+		if len(player.NewVolatilities) > drawIdx {
+			volatility = player.NewVolatilities[drawIdx]
+		}
+		matchRecordReq.Players = append(matchRecordReq.Players, dtos.PlayerRecordRequest{
+			Id:            player.Id,
+			OldRating:     player.Rating,
+			NewRating:     player.NewRatings[drawIdx],
+			OldRD:         player.RD,
+			NewRD:         player.NewRDs[drawIdx],
+			Team:          player.Team,
+			NewVolatility: volatility,
+			// Casual games still move the provisional rating of guests
+			Unrated: casual && (!player.Provisional || activeMatch.SimulId != ""),
+		})
+		matchRecordReq.Results = append(matchRecordReq.Results, 0.5)
+	}
+	for i, uci := range adjournment.Moves {
+		matchRecordReq.Plies = append(matchRecordReq.Plies, dtos.PlyRecordRequest{
+			Ply:   i + 1,
+			Uci:   uci,
+			Clock: moveClocks[i].String(),
+		})
+	}
+
+	date := time.Now()
+	if activeMatch.StartedAt != nil {
+		date = *activeMatch.StartedAt
+	}
+	white, black := teamOf(players, 0), teamOf(players, 1)
+	matchRecordReq.Pgn = pgn.Write(pgn.Game{
+		Event:       event(activeMatch),
+		Date:        date,
+		White:       teamName(white),
+		Black:       teamName(black),
+		WhiteElo:    averageRating(white),
+		BlackElo:    averageRating(black),
+		TimeControl: pgn.TimeControl(gameMode.Time, gameMode.Increment),
+		Termination: "Adjudication",
+		Outcome:     chess.Draw,
+		Positions:   game.Positions(),
+		Moves:       game.Moves(),
+		Clocks:      moveClocks,
+	})
+	return matchRecordReq, nil
+}
+
+func event(activeMatch entities.ActiveMatch) string {
+	switch {
+	case activeMatch.SimulId != "":
+		return "Casual simul game"
+	case activeMatch.Casual:
+		return "Casual game"
+	case activeMatch.TeamMode == entities.TeamModeHandAndBrain:
+		return "Rated hand and brain game"
+	default:
+		return "Rated game"
+	}
+}
+
+func teamOf(players []entities.Player, team int) []entities.Player {
+	members := make([]entities.Player, 0, len(players))
+	for _, player := range players {
+		if player.Team == team {
+			members = append(members, player)
+		}
+	}
+	return members
+}
+
+func teamName(team []entities.Player) string {
+	names := make([]string, 0, len(team))
+	for _, player := range team {
+		name := player.Username
+		if name == "" {
+			name = player.Id
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " & ")
+}
+
+func averageRating(team []entities.Player) float64 {
+	if len(team) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, player := range team {
+		sum += player.Rating
+	}
+	return sum / float64(len(team))
+}
@@ -0,0 +1,81 @@
+package adjourned
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+func testActiveMatch(deadline time.Time) entities.ActiveMatch {
+	return entities.ActiveMatch{
+		MatchId:  "match",
+		GameMode: "60+30",
+		Player1: entities.Player{
+			Id:         "white",
+			Rating:     1500,
+			RD:         100,
+			NewRatings: []float64{1510, 1502, 1490},
+			NewRDs:     []float64{90, 90, 90},
+		},
+		Player2: entities.Player{
+			Id:         "black",
+			Rating:     1500,
+			RD:         100,
+			NewRatings: []float64{1510, 1498, 1490},
+			NewRDs:     []float64{90, 90, 90},
+		},
+		Adjournment: &entities.Adjournment{
+			Moves:      []string{"e2e4", "e7e5"},
+			MoveClocks: []string{"59m58s", "59m57s"},
+			Clocks:     []string{"59m58s", "59m57s"},
+			Deadline:   deadline,
+		},
+	}
+}
+
+func TestRecordScoresExpiredAdjournmentAsDraw(t *testing.T) {
+	req, err := Record(testActiveMatch(time.Now().Add(-time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range req.Results {
+		if result != 0.5 {
+			t.Errorf("result of player %d = %v, want a draw", i, result)
+		}
+	}
+	if req.Players[0].NewRating != 1502 || req.Players[1].NewRating != 1498 {
+		t.Errorf("new ratings = %v/%v, want the draw ratings 1502/1498", req.Players[0].NewRating, req.Players[1].NewRating)
+	}
+	if req.Players[0].Unrated || req.Players[1].Unrated {
+		t.Error("player of a rated adjourned match is unrated")
+	}
+	if len(req.Plies) != 2 || req.Plies[1].Clock != "59m57s" {
+		t.Errorf("plies = %+v, want both saved moves with their clocks", req.Plies)
+	}
+	if !strings.Contains(req.Pgn, `[Result "1/2-1/2"]`) || !strings.Contains(req.Pgn, "1. e4 { [%clk 0:59:58] } e5") {
+		t.Errorf("unexpected pgn:\n%s", req.Pgn)
+	}
+}
+
+func TestRecordRejectsPendingAdjournment(t *testing.T) {
+	_, err := Record(testActiveMatch(time.Now().Add(time.Hour)))
+	if !errors.Is(err, ErrNotExpired) {
+		t.Errorf("err = %v, want %v", err, ErrNotExpired)
+	}
+}
+
+func TestRecordOfCasualAdjournmentRatesOnlyGuests(t *testing.T) {
+	activeMatch := testActiveMatch(time.Now().Add(-time.Minute))
+	activeMatch.Casual = true
+	activeMatch.Player1.Provisional = true
+	req, err := Record(activeMatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Players[0].Unrated || !req.Players[1].Unrated {
+		t.Errorf("unrated = %v/%v, want only the registered player unrated", req.Players[0].Unrated, req.Players[1].Unrated)
+	}
+}
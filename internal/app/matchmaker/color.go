@@ -0,0 +1,74 @@
+package matchmaker
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+// Number of recent results looked at to balance the colors of a player
+const colorHistorySize = 10
+
+/*
+AllocateColors function    reports whether two players must be swapped so that the first one plays white.
+An explicit preference of the first player is always kept. Otherwise the player who recently had white
+more often gets black, and equal balances are broken at random.
+*/
+func AllocateColors(balance1, balance2 int, preference string) (bool, string) {
+	switch {
+	case preference == entities.ColorWhite:
+		return false, entities.ColorAllocationPreference
+	case preference == entities.ColorBlack:
+		return true, entities.ColorAllocationPreference
+	case balance1 != balance2:
+		return balance1 > balance2, entities.ColorAllocationBalanced
+	default:
+		return rand.IntN(2) == 1, entities.ColorAllocationRandom
+	}
+}
+
+// allocateColors method    orders the tickets of a pairing so that the first one plays white
+func (m *Matchmaker) allocateColors(
+	ctx context.Context,
+	ticket1 entities.MatchmakingTicket,
+	ticket2 entities.MatchmakingTicket,
+) (
+	entities.MatchmakingTicket,
+	entities.MatchmakingTicket,
+	string,
+	error,
+) {
+	balance1, err := m.colorBalance(ctx, ticket1.UserId)
+	if err != nil {
+		return ticket1, ticket2, "", err
+	}
+	balance2, err := m.colorBalance(ctx, ticket2.UserId)
+	if err != nil {
+		return ticket1, ticket2, "", err
+	}
+	swap, allocation := AllocateColors(balance1, balance2, "")
+	if swap {
+		return ticket2, ticket1, allocation, nil
+	}
+	return ticket1, ticket2, allocation, nil
+}
+
+// colorBalance method    returns how many more of the user's recent games were played as white than as black
+func (m *Matchmaker) colorBalance(ctx context.Context, userId string) (int, error) {
+	matchResults, _, err := m.storageClient.FetchMatchResults(ctx, userId, nil, colorHistorySize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch match results: %w", err)
+	}
+	var balance int
+	for _, matchResult := range matchResults {
+		switch matchResult.Color {
+		case entities.ColorWhite:
+			balance++
+		case entities.ColorBlack:
+			balance--
+		}
+	}
+	return balance, nil
+}
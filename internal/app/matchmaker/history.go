@@ -0,0 +1,77 @@
+package matchmaker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// Number of recent results looked at to spot repeat pairings
+	opponentHistorySize = 10
+
+	// Rating points added to the cost of a pairing for each recent game between the two players
+	DefaultRepeatPenalty = 100.0
+)
+
+// playerHistory struct    is what the matchmaker knows about a queued player beyond the ticket
+type playerHistory struct {
+	blocked map[string]bool
+	// End times of the recent games against each opponent
+	recentOpponents map[string][]time.Time
+}
+
+// loadHistories method    loads the history of every player in the candidate pairings
+func (m *Matchmaker) loadHistories(
+	ctx context.Context,
+	candidates []pairing,
+) (
+	map[string]playerHistory,
+	error,
+) {
+	histories := make(map[string]playerHistory)
+	for _, candidate := range candidates {
+		for _, ticket := range candidate.tickets {
+			if _, ok := histories[ticket.UserId]; ok {
+				continue
+			}
+			history, err := m.loadHistory(ctx, ticket.UserId)
+			if err != nil {
+				return nil, err
+			}
+			histories[ticket.UserId] = history
+		}
+	}
+	return histories, nil
+}
+
+func (m *Matchmaker) loadHistory(ctx context.Context, userId string) (playerHistory, error) {
+	history := playerHistory{
+		blocked:         make(map[string]bool),
+		recentOpponents: make(map[string][]time.Time),
+	}
+
+	blockedIds, err := m.storageClient.FetchBlockedIds(ctx, userId)
+	if err != nil {
+		return playerHistory{}, fmt.Errorf("failed to fetch blocked ids: %w", err)
+	}
+	for _, blockedId := range blockedIds {
+		history.blocked[blockedId] = true
+	}
+
+	matchResults, _, err := m.storageClient.FetchMatchResults(ctx, userId, nil, opponentHistorySize)
+	if err != nil {
+		return playerHistory{}, fmt.Errorf("failed to fetch match results: %w", err)
+	}
+	for _, matchResult := range matchResults {
+		endedAt, err := time.Parse(time.RFC3339, matchResult.Timestamp)
+		if err != nil {
+			continue
+		}
+		history.recentOpponents[matchResult.OpponentId] = append(
+			history.recentOpponents[matchResult.OpponentId],
+			endedAt,
+		)
+	}
+	return history, nil
+}
@@ -0,0 +1,120 @@
+package matchmaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewaymanagementapi"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/utils"
+)
+
+// createMatch method    creates the active match of a pairing and takes both tickets off the queue
+func (m *Matchmaker) createMatch(
+	ctx context.Context,
+	ticket1 entities.MatchmakingTicket,
+	ticket2 entities.MatchmakingTicket,
+	serverIp string,
+) (
+	entities.ActiveMatch,
+	error,
+) {
+	ticket1, ticket2, colorAllocation, err := m.allocateColors(ctx, ticket1, ticket2)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to allocate colors: %w", err)
+	}
+
+	casual := ticket1.Pool == entities.MatchmakingPoolCasual
+	match := entities.ActiveMatch{
+		MatchId:         utils.GenerateUUID(),
+		ConversationId:  utils.GenerateUUID(),
+		PartitionKey:    "ActiveMatches",
+		GameMode:        ticket1.GameMode,
+		Casual:          casual,
+		ColorAllocation: colorAllocation,
+		Server:          serverIp,
+		CreatedAt:       time.Now(),
+	}
+
+	// Pre-calculate players' rating in each possible outcome, in the category of the game mode
+	category, err := entities.RatingCategoryOf(ticket1.GameMode)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to get rating category: %w", err)
+	}
+	userRating1, err := m.storageClient.GetUserRating(ctx, ticket1.UserId, category)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to get user rating: %w", err)
+	}
+	userRating2, err := m.storageClient.GetUserRating(ctx, ticket2.UserId, category)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to get user rating: %w", err)
+	}
+
+	// Casual games only move the provisional rating of guests
+	if casual && !userRating1.IsGuest() {
+		match.Player1 = unratedPlayer(userRating1)
+	} else {
+		match.Player1 = ratedPlayer(userRating1, userRating2, match.CreatedAt)
+	}
+	if casual && !userRating2.IsGuest() {
+		match.Player2 = unratedPlayer(userRating2)
+	} else {
+		match.Player2 = ratedPlayer(userRating2, userRating1, match.CreatedAt)
+	}
+	match.AverageRating = (match.Player1.Rating + match.Player2.Rating) / 2
+
+	// Queue removal, user matches, the match and its spectator conversation are written all or nothing
+	err = m.storageClient.CreateMatch(
+		ctx,
+		match,
+		[]entities.MatchmakingTicket{ticket1, ticket2},
+		entities.SpectatorConversation{
+			MatchId:        match.MatchId,
+			ConversationId: utils.GenerateUUID(),
+		},
+	)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to create match: %w", err)
+	}
+
+	return match, nil
+}
+
+// notifyUser method    pushes the match to the user's queueing socket and closes it
+func (m *Matchmaker) notifyUser(ctx context.Context, userId string, data []byte) error {
+	// Get user ID from DynamoDB
+	connection, err := m.storageClient.GetConnectionByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, storage.ErrConnectionNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	_, err = m.apigatewayClient.PostToConnection(
+		ctx,
+		&apigatewaymanagementapi.PostToConnectionInput{
+			ConnectionId: aws.String(connection.Id),
+			Data:         data,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to post to connect: %w", err)
+	}
+
+	_, err = m.apigatewayClient.DeleteConnection(
+		ctx,
+		&apigatewaymanagementapi.DeleteConnectionInput{
+			ConnectionId: aws.String(connection.Id),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/apigatewaymanagementapi"
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/compute"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"go.uber.org/zap"
+)
+
+var ErrServerNotAvailable = errors.New("server not available")
+
+type Config struct {
+	ClusterName  string
+	ServiceName  string
+	TicketSecret []byte
+	// Overrides the default rating window of the listed game modes
+	RatingWindows map[string]RatingWindow
+	// Players who met within this long are never paired again, zero only ranks them lower
+	AvoidRecentOpponentFor time.Duration
+	RepeatPenalty          float64
+}
+
+/*
+Matchmaker struct    pairs the queued matchmaking tickets in batches.
+Each run reads the whole queue of every game mode and pool, so pairings are chosen
+across all waiting players instead of whoever happened to call last.
+*/
+type Matchmaker struct {
+	storageClient    *storage.Client
+	computeClient    *compute.Client
+	apigatewayClient *apigatewaymanagementapi.Client
+	cfg              Config
+}
+
+func NewMatchmaker(
+	storageClient *storage.Client,
+	computeClient *compute.Client,
+	apigatewayClient *apigatewaymanagementapi.Client,
+	cfg Config,
+) *Matchmaker {
+	return &Matchmaker{
+		storageClient:    storageClient,
+		computeClient:    computeClient,
+		apigatewayClient: apigatewayClient,
+		cfg:              cfg,
+	}
+}
+
+/*
+Run method    pairs every queue once and returns the number of matches created.
+A failing queue is logged and skipped, so it never holds the other queues back.
+*/
+func (m *Matchmaker) Run(ctx context.Context) (int, error) {
+	var (
+		created int
+		errs    []error
+	)
+	for _, gameMode := range entities.GameModes() {
+		for _, pool := range []string{
+			entities.MatchmakingPoolRated,
+			entities.MatchmakingPoolCasual,
+		} {
+			n, err := m.runQueue(ctx, gameMode, pool)
+			created += n
+			if err != nil {
+				err = fmt.Errorf("failed to run queue %s/%s: %w", gameMode, pool, err)
+				logging.Error("failed to run queue", zap.Error(err))
+				errs = append(errs, err)
+			}
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+func (m *Matchmaker) runQueue(ctx context.Context, gameMode, pool string) (int, error) {
+	tickets, err := m.storageClient.FetchMatchmakingTickets(ctx, gameMode, pool)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch matchmaking tickets: %w", err)
+	}
+	now := time.Now()
+	tickets = m.liveTickets(ctx, tickets, now)
+
+	// Team tickets fill team matches of their own mode, the rest is paired one against one
+	var (
+		created     int
+		teamTickets = make(map[string][]entities.MatchmakingTicket)
+		standard    = make([]entities.MatchmakingTicket, 0, len(tickets))
+	)
+	for _, ticket := range tickets {
+		if teamMode := teamModeOf(ticket); teamMode != entities.TeamModeStandard {
+			teamTickets[teamMode] = append(teamTickets[teamMode], ticket)
+			continue
+		}
+		standard = append(standard, ticket)
+	}
+	var errs []error
+	for teamMode, queue := range teamTickets {
+		n, err := m.runTeamQueue(ctx, queue, gameMode, teamMode, now)
+		created += n
+		if err != nil {
+			err = fmt.Errorf("failed to run team queue %s: %w", teamMode, err)
+			logging.Error("failed to run team queue", zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+	n, err := m.pairQueue(ctx, standard, gameMode, now)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return created + n, errors.Join(errs...)
+}
+
+// pairQueue method    pairs the live standard tickets of one queue into matches
+func (m *Matchmaker) pairQueue(
+	ctx context.Context,
+	tickets []entities.MatchmakingTicket,
+	gameMode string,
+	now time.Time,
+) (int, error) {
+	if len(tickets) < 2 {
+		return 0, nil
+	}
+
+	candidates := candidatePairings(
+		tickets,
+		RatingWindowOf(m.cfg.RatingWindows, gameMode),
+		now,
+	)
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	histories, err := m.loadHistories(ctx, candidates)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load histories: %w", err)
+	}
+	candidates = rankCandidates(
+		candidates,
+		histories,
+		m.cfg.AvoidRecentOpponentFor,
+		m.cfg.RepeatPenalty,
+		now,
+	)
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	serverIp, err := m.availableServerIp(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		created int
+		// Users that got a match or can no longer be matched in this run
+		taken = make(map[string]bool, len(tickets))
+	)
+	for _, p := range candidates {
+		if taken[p.tickets[0].UserId] || taken[p.tickets[1].UserId] {
+			continue
+		}
+		match, err := m.createMatch(ctx, p.tickets[0], p.tickets[1], serverIp)
+		if err != nil {
+			// Another writer took one of the players, the other one moves on to its next candidate
+			var conflict *storage.MatchConflictError
+			if errors.As(err, &conflict) {
+				for _, userId := range conflict.UserIds {
+					taken[userId] = true
+				}
+				continue
+			}
+			logging.Error(
+				"failed to create match",
+				zap.String("user1_id", p.tickets[0].UserId),
+				zap.String("user2_id", p.tickets[1].UserId),
+				zap.Error(err),
+			)
+			continue
+		}
+		taken[p.tickets[0].UserId] = true
+		taken[p.tickets[1].UserId] = true
+		created++
+
+		matchResp := dtos.ActiveMatchResponseFromEntity(match)
+		matchResp.Ticket, err = auth.SignMatchTicket(match, false, m.cfg.TicketSecret)
+		if err != nil {
+			return created, fmt.Errorf("failed to sign match ticket: %w", err)
+		}
+		matchRespJson, err := json.Marshal(matchResp)
+		if err != nil {
+			return created, fmt.Errorf("failed to marshal response: %w", err)
+		}
+		for _, ticket := range p.tickets {
+			if err := m.notifyUser(ctx, ticket.UserId, matchRespJson); err != nil {
+				logging.Error(
+					"failed to notify queueing user",
+					zap.String("user_id", ticket.UserId),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+	return created, nil
+}
+
+// liveTickets method    drops the tickets that missed their heartbeats and removes them from the queue
+func (m *Matchmaker) liveTickets(
+	ctx context.Context,
+	tickets []entities.MatchmakingTicket,
+	now time.Time,
+) []entities.MatchmakingTicket {
+	live := make([]entities.MatchmakingTicket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if !ticket.Expired(now) {
+			live = append(live, ticket)
+			continue
+		}
+		if err := m.storageClient.DeleteMatchmakingTicket(ctx, ticket.UserId); err != nil {
+			logging.Error(
+				"failed to delete expired matchmaking ticket",
+				zap.String("user_id", ticket.UserId),
+				zap.Error(err),
+			)
+		}
+	}
+	return live
+}
+
+// availableServerIp method    waits for a game server that can accept new matches
+func (m *Matchmaker) availableServerIp(ctx context.Context) (string, error) {
+	var (
+		serverIp     string
+		pendingCount int
+		err          error
+	)
+	for range 5 {
+		serverIp, pendingCount, err = m.computeClient.GetAvailableServerIp(
+			ctx,
+			m.cfg.ClusterName,
+			m.cfg.ServiceName,
+		)
+		if err == nil {
+			return serverIp, nil
+		}
+		if err == compute.ErrNoServerAvailable && pendingCount == 0 {
+			m.computeClient.StartNewTask(ctx, m.cfg.ClusterName, m.cfg.ServiceName)
+		}
+		time.Sleep(5 + time.Duration(rand.IntN(5))*time.Second)
+	}
+	return "", fmt.Errorf("%w: %w", ErrServerNotAvailable, err)
+}
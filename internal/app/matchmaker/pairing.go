@@ -0,0 +1,117 @@
+package matchmaker
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+type pairing struct {
+	tickets [2]entities.MatchmakingTicket
+	// Lower is a better match
+	cost float64
+}
+
+/*
+acceptable function    reports whether the two tickets can be paired.
+The rating gap must fit the average of both windows, so a long wait on one side is enough to widen it,
+and the client chosen limits of each ticket are always honoured.
+*/
+func acceptable(
+	a, b entities.MatchmakingTicket,
+	window RatingWindow,
+	now time.Time,
+) bool {
+	if a.UserId == b.UserId ||
+		!sameTeamMode(a, b) ||
+		!withinRange(a, b.UserRating) ||
+		!withinRange(b, a.UserRating) {
+		return false
+	}
+	gap := (window.gap(a, now) + window.gap(b, now)) / 2
+	return math.Abs(a.UserRating-b.UserRating) <= gap
+}
+
+func withinRange(ticket entities.MatchmakingTicket, rating float64) bool {
+	return !ticket.HasRatingRange() ||
+		(rating >= ticket.MinRating && rating <= ticket.MaxRating)
+}
+
+func sameTeamMode(a, b entities.MatchmakingTicket) bool {
+	return teamModeOf(a) == teamModeOf(b)
+}
+
+// teamModeOf function    returns the team mode of a ticket, tickets queued before team modes are standard
+func teamModeOf(ticket entities.MatchmakingTicket) string {
+	if ticket.TeamMode == "" {
+		return entities.TeamModeStandard
+	}
+	return ticket.TeamMode
+}
+
+func pairingCost(a, b entities.MatchmakingTicket) float64 {
+	return math.Abs(a.UserRating - b.UserRating)
+}
+
+/*
+candidatePairings function    ranks every acceptable pair of one queue by cost, best first.
+Taking them greedily in that order matches the closest pairs of the whole pool before the looser ones,
+and a pair that can no longer be created simply leaves its players to their next candidates.
+*/
+func candidatePairings(
+	tickets []entities.MatchmakingTicket,
+	window RatingWindow,
+	now time.Time,
+) []pairing {
+	candidates := make([]pairing, 0, len(tickets))
+	for i := range tickets {
+		for j := i + 1; j < len(tickets); j++ {
+			if !acceptable(tickets[i], tickets[j], window, now) {
+				continue
+			}
+			candidates = append(candidates, pairing{
+				tickets: [2]entities.MatchmakingTicket{tickets[i], tickets[j]},
+				cost:    pairingCost(tickets[i], tickets[j]),
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].cost < candidates[j].cost
+	})
+	return candidates
+}
+
+/*
+rankCandidates function    applies the players' histories to the candidate pairings.
+Pairings where either player blocked the other, or where both met within avoidFor, are dropped.
+The others cost more for each recent game between the two players, so a fresh opponent of a similar
+rating is preferred over a rematch.
+*/
+func rankCandidates(
+	candidates []pairing,
+	histories map[string]playerHistory,
+	avoidFor time.Duration,
+	repeatPenalty float64,
+	now time.Time,
+) []pairing {
+	ranked := make([]pairing, 0, len(candidates))
+	for _, candidate := range candidates {
+		a, b := candidate.tickets[0].UserId, candidate.tickets[1].UserId
+		historyA, historyB := histories[a], histories[b]
+		if historyA.blocked[b] || historyB.blocked[a] {
+			continue
+		}
+		games := historyA.recentOpponents[b]
+		if avoidFor > 0 && len(games) > 0 && now.Sub(games[0]) < avoidFor {
+			continue
+		}
+		candidate.cost += repeatPenalty * float64(len(games))
+		ranked = append(ranked, candidate)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].cost < ranked[j].cost
+	})
+	return ranked
+}
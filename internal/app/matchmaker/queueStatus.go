@@ -0,0 +1,62 @@
+package matchmaker
+
+import (
+	"math"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+/*
+QueueStatus function    reports where a ticket stands in the queue of its game mode, pool and team mode.
+The estimated wait is how long until the rating windows widen enough to accept the closest live ticket,
+it is left out when no ticket in the pool can ever be accepted.
+*/
+func QueueStatus(
+	ticket entities.MatchmakingTicket,
+	queue []entities.MatchmakingTicket,
+	window RatingWindow,
+	now time.Time,
+) dtos.QueueStatusResponse {
+	status := dtos.QueueStatusResponse{
+		Type:     dtos.QueueStatusType,
+		GameMode: ticket.GameMode,
+		Casual:   ticket.Pool == entities.MatchmakingPoolCasual,
+		Position: 1,
+		PoolSize: 1,
+	}
+	var (
+		wait  = math.Inf(1)
+		limit = maxWindowWait(window)
+	)
+	for _, other := range queue {
+		if other.UserId == ticket.UserId || other.Expired(now) || !sameTeamMode(ticket, other) {
+			continue
+		}
+		status.PoolSize++
+		if other.EnqueuedAt.Before(ticket.EnqueuedAt) {
+			status.Position++
+		}
+		for t := 0.0; t <= limit && t < wait; t++ {
+			at := now.Add(time.Duration(t) * time.Second)
+			if acceptable(ticket, other, window, at) {
+				wait = t
+				break
+			}
+		}
+	}
+	if !math.IsInf(wait, 1) {
+		seconds := int64(wait)
+		status.EstimatedWait = &seconds
+	}
+	return status
+}
+
+// maxWindowWait function    returns the seconds after which a fresh ticket's window stops growing
+func maxWindowWait(window RatingWindow) float64 {
+	if window.Growth <= 0 {
+		return 0
+	}
+	return math.Ceil(math.Max(window.Max-window.Initial, 0) / window.Growth)
+}
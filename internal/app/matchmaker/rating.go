@@ -0,0 +1,101 @@
+package matchmaker
+
+import (
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/rating"
+)
+
+// currentRating function    returns the user's rating with the RD grown by the time since the last rated game
+func currentRating(userRating entities.UserRating, now time.Time) rating.Rating {
+	r := rating.Rating{
+		Rating:     userRating.Rating,
+		RD:         userRating.RD,
+		Volatility: userRating.Volatility,
+	}
+	if userRating.RatedAt == nil {
+		return rating.DefaultConfig.Clamp(r)
+	}
+	return rating.DefaultConfig.Decay(r, now.Sub(*userRating.RatedAt))
+}
+
+// ratedPlayer function    precomputes the rating, RD and volatility of a player after a win, a draw and a loss
+func ratedPlayer(
+	userRating entities.UserRating,
+	opponentRating entities.UserRating,
+	now time.Time,
+) entities.Player {
+	r := currentRating(userRating, now)
+	return outcomePlayer(userRating, r, rating.DefaultConfig.Outcomes(r, currentRating(opponentRating, now)))
+}
+
+/*
+teamPlayers function    precomputes the players of a team from one team level update against the opposing team.
+Every rated member takes the same change of rating, RD and volatility.
+*/
+func teamPlayers(
+	team []entities.UserRating,
+	opponents []entities.UserRating,
+	casual bool,
+	now time.Time,
+) []entities.Player {
+	members := make([]rating.Rating, 0, len(team))
+	for _, userRating := range team {
+		members = append(members, currentRating(userRating, now))
+	}
+	opponentRatings := make([]rating.Rating, 0, len(opponents))
+	for _, userRating := range opponents {
+		opponentRatings = append(opponentRatings, currentRating(userRating, now))
+	}
+	outcomes := rating.DefaultConfig.TeamOutcomes(members, opponentRatings)
+
+	players := make([]entities.Player, 0, len(team))
+	for i, userRating := range team {
+		// Casual games only move the provisional rating of guests
+		if casual && !userRating.IsGuest() {
+			players = append(players, unratedPlayer(userRating))
+			continue
+		}
+		players = append(players, outcomePlayer(userRating, members[i], outcomes[i]))
+	}
+	return players
+}
+
+// outcomePlayer function    returns a player with its rating after a win, a draw and a loss
+func outcomePlayer(
+	userRating entities.UserRating,
+	r rating.Rating,
+	outcomes [3]rating.Rating,
+) entities.Player {
+	player := entities.Player{
+		Id:              userRating.UserId,
+		Username:        userRating.Username,
+		Rating:          userRating.Rating,
+		RD:              r.RD,
+		Volatility:      r.Volatility,
+		NewRatings:      make([]float64, 0, len(outcomes)),
+		NewRDs:          make([]float64, 0, len(outcomes)),
+		NewVolatilities: make([]float64, 0, len(outcomes)),
+		Provisional:     userRating.IsGuest(),
+	}
+	for _, outcome := range outcomes {
+		player.NewRatings = append(player.NewRatings, outcome.Rating)
+		player.NewRDs = append(player.NewRDs, outcome.RD)
+		player.NewVolatilities = append(player.NewVolatilities, outcome.Volatility)
+	}
+	return player
+}
+
+// unratedPlayer function    returns a player whose rating stays the same whatever the result
+func unratedPlayer(userRating entities.UserRating) entities.Player {
+	return entities.Player{
+		Id:         userRating.UserId,
+		Username:   userRating.Username,
+		Rating:     userRating.Rating,
+		RD:         userRating.RD,
+		Volatility: userRating.Volatility,
+		NewRatings: []float64{userRating.Rating, userRating.Rating, userRating.Rating},
+		NewRDs:     []float64{userRating.RD, userRating.RD, userRating.RD},
+	}
+}
@@ -0,0 +1,295 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/aws/storage"
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"github.com/chess-vn/slchess/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type teamGroup struct {
+	// Ordered by rating, highest first
+	tickets []entities.MatchmakingTicket
+	// Lower is a better match
+	cost float64
+}
+
+/*
+candidateTeamGroups function    ranks the groups that can fill both teams of a team match, best first.
+Groups are runs of neighbouring tickets ordered by rating, so a group gathers close ratings,
+and every two players of a group must be acceptable to each other.
+*/
+func candidateTeamGroups(
+	tickets []entities.MatchmakingTicket,
+	teamSize int,
+	window RatingWindow,
+	now time.Time,
+) []teamGroup {
+	size := 2 * teamSize
+	sorted := slices.Clone(tickets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UserRating > sorted[j].UserRating
+	})
+	var groups []teamGroup
+	for i := 0; i+size <= len(sorted); i++ {
+		group := sorted[i : i+size]
+		if !acceptableGroup(group, window, now) {
+			continue
+		}
+		groups = append(groups, teamGroup{
+			tickets: slices.Clone(group),
+			cost:    group[0].UserRating - group[size-1].UserRating,
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].cost < groups[j].cost
+	})
+	return groups
+}
+
+func acceptableGroup(
+	tickets []entities.MatchmakingTicket,
+	window RatingWindow,
+	now time.Time,
+) bool {
+	for i := range tickets {
+		for j := i + 1; j < len(tickets); j++ {
+			if !acceptable(tickets[i], tickets[j], window, now) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+/*
+splitTeams function    deals the tickets of a group, highest rating first, into two teams of close strength.
+The teams pick in turns 1-2-2-1, so each team stays ordered by rating.
+*/
+func splitTeams(tickets []entities.MatchmakingTicket) [2][]entities.MatchmakingTicket {
+	var teams [2][]entities.MatchmakingTicket
+	for i, ticket := range tickets {
+		team := i % 2
+		if (i/2)%2 == 1 {
+			team = 1 - team
+		}
+		teams[team] = append(teams[team], ticket)
+	}
+	return teams
+}
+
+// runTeamQueue method    groups the live tickets of one team mode queue into team matches
+func (m *Matchmaker) runTeamQueue(
+	ctx context.Context,
+	tickets []entities.MatchmakingTicket,
+	gameMode string,
+	teamMode string,
+	now time.Time,
+) (int, error) {
+	teamSize, err := entities.TeamSize(teamMode)
+	if err != nil {
+		return 0, err
+	}
+	if len(tickets) < 2*teamSize {
+		return 0, nil
+	}
+	groups := candidateTeamGroups(
+		tickets,
+		teamSize,
+		RatingWindowOf(m.cfg.RatingWindows, gameMode),
+		now,
+	)
+	groups, err = m.dropBlockedGroups(ctx, groups)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load histories: %w", err)
+	}
+	if len(groups) == 0 {
+		return 0, nil
+	}
+
+	serverIp, err := m.availableServerIp(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		created int
+		// Users that got a match or can no longer be matched in this run
+		taken = make(map[string]bool, len(tickets))
+	)
+	for _, group := range groups {
+		if slices.ContainsFunc(group.tickets, func(ticket entities.MatchmakingTicket) bool {
+			return taken[ticket.UserId]
+		}) {
+			continue
+		}
+		match, err := m.createTeamMatch(ctx, splitTeams(group.tickets), teamMode, serverIp)
+		if err != nil {
+			// Another writer took one of the players, the others move on to their next groups
+			var conflict *storage.MatchConflictError
+			if errors.As(err, &conflict) {
+				for _, userId := range conflict.UserIds {
+					taken[userId] = true
+				}
+				continue
+			}
+			logging.Error(
+				"failed to create team match",
+				zap.String("team_mode", teamMode),
+				zap.Error(err),
+			)
+			continue
+		}
+		for _, ticket := range group.tickets {
+			taken[ticket.UserId] = true
+		}
+		created++
+
+		matchResp := dtos.ActiveMatchResponseFromEntity(match)
+		matchResp.Ticket, err = auth.SignMatchTicket(match, false, m.cfg.TicketSecret)
+		if err != nil {
+			return created, fmt.Errorf("failed to sign match ticket: %w", err)
+		}
+		matchRespJson, err := json.Marshal(matchResp)
+		if err != nil {
+			return created, fmt.Errorf("failed to marshal response: %w", err)
+		}
+		for _, ticket := range group.tickets {
+			if err := m.notifyUser(ctx, ticket.UserId, matchRespJson); err != nil {
+				logging.Error(
+					"failed to notify queueing user",
+					zap.String("user_id", ticket.UserId),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+	return created, nil
+}
+
+// dropBlockedGroups method    leaves out the groups where a player blocked another one
+func (m *Matchmaker) dropBlockedGroups(ctx context.Context, groups []teamGroup) ([]teamGroup, error) {
+	histories := make(map[string]playerHistory)
+	kept := make([]teamGroup, 0, len(groups))
+	for _, group := range groups {
+		for _, ticket := range group.tickets {
+			if _, ok := histories[ticket.UserId]; ok {
+				continue
+			}
+			history, err := m.loadHistory(ctx, ticket.UserId)
+			if err != nil {
+				return nil, err
+			}
+			histories[ticket.UserId] = history
+		}
+		blocked := false
+		for _, a := range group.tickets {
+			for _, b := range group.tickets {
+				blocked = blocked || histories[a.UserId].blocked[b.UserId]
+			}
+		}
+		if !blocked {
+			kept = append(kept, group)
+		}
+	}
+	return kept, nil
+}
+
+/*
+createTeamMatch method    creates the active match of two teams and takes every ticket off the queue.
+The first player of each team is the highest rated one, it plays the brain in hand-and-brain.
+*/
+func (m *Matchmaker) createTeamMatch(
+	ctx context.Context,
+	teams [2][]entities.MatchmakingTicket,
+	teamMode string,
+	serverIp string,
+) (
+	entities.ActiveMatch,
+	error,
+) {
+	// The teams are already balanced by rating, the colors are left to chance
+	if rand.IntN(2) == 1 {
+		teams[0], teams[1] = teams[1], teams[0]
+	}
+
+	gameMode := teams[0][0].GameMode
+	casual := teams[0][0].Pool == entities.MatchmakingPoolCasual
+	match := entities.ActiveMatch{
+		MatchId:         utils.GenerateUUID(),
+		ConversationId:  utils.GenerateUUID(),
+		PartitionKey:    "ActiveMatches",
+		TeamMode:        teamMode,
+		GameMode:        gameMode,
+		Casual:          casual,
+		ColorAllocation: entities.ColorAllocationRandom,
+		Server:          serverIp,
+		CreatedAt:       time.Now(),
+	}
+
+	// Pre-calculate players' rating in each possible outcome, in the category of the game mode
+	category, err := entities.RatingCategoryOf(gameMode)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to get rating category: %w", err)
+	}
+	var userRatings [2][]entities.UserRating
+	for side, team := range teams {
+		for _, ticket := range team {
+			userRating, err := m.storageClient.GetUserRating(ctx, ticket.UserId, category)
+			if err != nil {
+				return entities.ActiveMatch{}, fmt.Errorf("failed to get user rating: %w", err)
+			}
+			userRatings[side] = append(userRatings[side], userRating)
+		}
+	}
+
+	var ratingSum float64
+	for side := range teams {
+		players := teamPlayers(userRatings[side], userRatings[1-side], casual, match.CreatedAt)
+		for i := range players {
+			players[i].Team = side
+			if teamMode == entities.TeamModeHandAndBrain {
+				players[i].Role = entities.RoleHand
+				if i == 0 {
+					players[i].Role = entities.RoleBrain
+				}
+			}
+			ratingSum += players[i].Rating
+		}
+		if side == 0 {
+			match.Player1 = players[0]
+		} else {
+			match.Player2 = players[0]
+		}
+		match.Teammates = append(match.Teammates, players[1:]...)
+	}
+	match.AverageRating = ratingSum / float64(len(teams[0])+len(teams[1]))
+
+	// Queue removal, user matches, the match and its spectator conversation are written all or nothing
+	err = m.storageClient.CreateMatch(
+		ctx,
+		match,
+		append(slices.Clone(teams[0]), teams[1]...),
+		entities.SpectatorConversation{
+			MatchId:        match.MatchId,
+			ConversationId: utils.GenerateUUID(),
+		},
+	)
+	if err != nil {
+		return entities.ActiveMatch{}, fmt.Errorf("failed to create match: %w", err)
+	}
+
+	return match, nil
+}
@@ -0,0 +1,72 @@
+package matchmaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+/*
+RatingWindow struct    is the curve of the rating gap a ticket accepts.
+The gap starts at Initial, grows by Growth points per second of waiting up to Max,
+and RDFactor times the player's rating deviation is added on top so provisional players match wider.
+*/
+type RatingWindow struct {
+	Initial  float64 `json:"initial"`
+	Growth   float64 `json:"growth"`
+	Max      float64 `json:"max"`
+	RDFactor float64 `json:"rdFactor"`
+}
+
+var DefaultRatingWindow = RatingWindow{
+	Initial:  50,
+	Growth:   5,
+	Max:      500,
+	RDFactor: 0.5,
+}
+
+// Short games are worth starting sooner, long ones are worth waiting for a closer opponent
+var defaultRatingWindows = map[string]RatingWindow{
+	"1+0":   {Initial: 100, Growth: 10, Max: 600, RDFactor: 0.5},
+	"60+30": {Initial: 50, Growth: 2, Max: 400, RDFactor: 0.5},
+}
+
+// ParseRatingWindows function    reads per game mode windows written as a JSON object keyed by game mode
+func ParseRatingWindows(value string) (map[string]RatingWindow, error) {
+	windows := make(map[string]RatingWindow)
+	if value == "" {
+		return windows, nil
+	}
+	if err := json.Unmarshal([]byte(value), &windows); err != nil {
+		return nil, fmt.Errorf("failed to parse rating windows: %w", err)
+	}
+	for gameMode := range windows {
+		if err := entities.ValidateGameMode(gameMode); err != nil {
+			return nil, fmt.Errorf("invalid rating window game mode %s: %w", gameMode, err)
+		}
+	}
+	return windows, nil
+}
+
+// gap method    returns the rating gap the ticket accepts at the given time
+func (w RatingWindow) gap(ticket entities.MatchmakingTicket, now time.Time) float64 {
+	waited := now.Sub(ticket.EnqueuedAt).Seconds()
+	if ticket.EnqueuedAt.IsZero() || waited < 0 {
+		waited = 0
+	}
+	return math.Min(w.Initial+w.Growth*waited, w.Max) + w.RDFactor*ticket.UserRD
+}
+
+// RatingWindowOf function    returns the configured window of a game mode, falling back to the defaults
+func RatingWindowOf(windows map[string]RatingWindow, gameMode string) RatingWindow {
+	if window, ok := windows[gameMode]; ok {
+		return window
+	}
+	if window, ok := defaultRatingWindows[gameMode]; ok {
+		return window
+	}
+	return DefaultRatingWindow
+}
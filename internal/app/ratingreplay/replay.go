@@ -0,0 +1,209 @@
+package ratingreplay
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/rating"
+)
+
+var (
+	ErrUnknownGameMode = errors.New("unknown game mode")
+	ErrMissingResults  = errors.New("missing results")
+
+	pgnResultTag = regexp.MustCompile(`\[Result "([^"]*)"\]`)
+)
+
+// Key struct    identifies the rating of a user in a category
+type Key struct {
+	UserId   string
+	Category string
+}
+
+// State struct    is a replayed rating and the history that led to it
+type State struct {
+	Rating  rating.Rating
+	RatedAt time.Time
+	History []entities.RatingHistoryEntry
+}
+
+/*
+Replayer struct    rates match records again, in the order they are replayed.
+It applies the same rules as the endGame lambda: a player is rated unless the record
+marks them unrated, after the RD grew with the time since their previous game.
+Each team is rated once and its members share the change.
+*/
+type Replayer struct {
+	cfg rating.Config
+	// replayed reports whether the rating of a user in a category is recomputed,
+	// other players keep the rating recorded in the match records
+	replayed func(Key) bool
+	states   map[Key]*State
+}
+
+func NewReplayer(cfg rating.Config, replayed func(Key) bool) *Replayer {
+	return &Replayer{
+		cfg:      cfg,
+		replayed: replayed,
+		states:   make(map[Key]*State),
+	}
+}
+
+// SortMatchRecords function    orders match records by the time they ended
+func SortMatchRecords(matchRecords []entities.MatchRecord) {
+	slices.SortFunc(matchRecords, func(a, b entities.MatchRecord) int {
+		if c := a.EndedAt.Compare(b.EndedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.MatchId, b.MatchId)
+	})
+}
+
+// Replay method    rates the players of one match record
+func (r *Replayer) Replay(matchRecord entities.MatchRecord) error {
+	// Casual games and games without an outcome left every rating as it was
+	if !matchRecord.Rated() {
+		return nil
+	}
+	category, err := entities.RatingCategoryOf(matchRecord.GameMode)
+	if err != nil {
+		return ErrUnknownGameMode
+	}
+	results, err := Results(matchRecord)
+	if err != nil {
+		return err
+	}
+
+	// Every player is rated against the ratings from before the match
+	current := make([]rating.Rating, len(matchRecord.Players))
+	for i, player := range matchRecord.Players {
+		current[i] = r.current(Key{player.Id, category}, player, matchRecord.StartedAt)
+	}
+
+	updated := r.teamUpdates(matchRecord.Players, current, results)
+
+	for i, player := range matchRecord.Players {
+		key := Key{player.Id, category}
+		if player.Unrated || !r.replayed(key) {
+			continue
+		}
+		state := r.state(key)
+		// TeamUpdate already keeps every member within the RD bounds with the same change
+		state.Rating = updated[i]
+		state.RatedAt = matchRecord.EndedAt
+		state.History = append(state.History, entities.NewRatingHistoryEntry(
+			player.Id,
+			category,
+			matchRecord.MatchId,
+			current[i].Rating,
+			state.Rating.Rating,
+			state.Rating.RD,
+			matchRecord.EndedAt,
+		))
+	}
+	return nil
+}
+
+// States method    returns the replayed rating of every user rated so far
+func (r *Replayer) States() map[Key]State {
+	states := make(map[Key]State, len(r.states))
+	for key, state := range r.states {
+		states[key] = *state
+	}
+	return states
+}
+
+// current method    returns the rating of a player when the match started
+func (r *Replayer) current(key Key, player entities.PlayerRecord, startedAt time.Time) rating.Rating {
+	if !r.replayed(key) {
+		// Records written before RDs were stored fall back to the initial RD
+		rd := player.OldRD
+		if rd == 0 {
+			rd = entities.InitialRD
+		}
+		return r.cfg.Clamp(rating.Rating{Rating: player.OldRating, RD: rd})
+	}
+	state, ok := r.states[key]
+	if !ok {
+		return r.cfg.Clamp(rating.Rating{Rating: entities.InitialRating, RD: entities.InitialRD})
+	}
+	return r.cfg.Decay(state.Rating, startedAt.Sub(state.RatedAt))
+}
+
+func (r *Replayer) state(key Key) *State {
+	state, ok := r.states[key]
+	if !ok {
+		state = new(State)
+		r.states[key] = state
+	}
+	return state
+}
+
+// teamUpdates method    rates each team once against the other, in the order of the players
+func (r *Replayer) teamUpdates(
+	players []entities.PlayerRecord,
+	current []rating.Rating,
+	results []float64,
+) []rating.Rating {
+	updated := make([]rating.Rating, len(players))
+	done := make(map[int]bool)
+	for i, player := range players {
+		if done[player.Team] {
+			continue
+		}
+		done[player.Team] = true
+		var (
+			members   []int
+			ratings   []rating.Rating
+			opponents []rating.Rating
+		)
+		for j, other := range players {
+			if other.Team == player.Team {
+				members = append(members, j)
+				ratings = append(ratings, current[j])
+			} else {
+				opponents = append(opponents, current[j])
+			}
+		}
+		for k, newRating := range r.cfg.TeamUpdate(ratings, opponents, results[i]) {
+			updated[members[k]] = newRating
+		}
+	}
+	return updated
+}
+
+// Results function    returns the score of each player, from the PGN result on records without scores
+func Results(matchRecord entities.MatchRecord) ([]float64, error) {
+	if len(matchRecord.Results) == len(matchRecord.Players) {
+		return matchRecord.Results, nil
+	}
+	match := pgnResultTag.FindStringSubmatch(matchRecord.Pgn)
+	if match == nil {
+		return nil, ErrMissingResults
+	}
+	var whiteScore float64
+	switch match[1] {
+	case "1-0":
+		whiteScore = 1
+	case "0-1":
+		whiteScore = 0
+	case "1/2-1/2":
+		whiteScore = 0.5
+	default:
+		return nil, fmt.Errorf("%w: unfinished game", ErrMissingResults)
+	}
+	results := make([]float64, 0, len(matchRecord.Players))
+	for _, player := range matchRecord.Players {
+		if player.Team == 0 {
+			results = append(results, whiteScore)
+		} else {
+			results = append(results, 1-whiteScore)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,52 @@
+package ratingreplay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/rating"
+)
+
+func replayAll(Key) bool { return true }
+
+func testRecord(unrated bool, results ...float64) entities.MatchRecord {
+	endedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	return entities.MatchRecord{
+		MatchId:  "match",
+		GameMode: "10+0",
+		Players: []entities.PlayerRecord{
+			{Id: "white", OldRating: 1500, NewRating: 1500, Team: 0, Unrated: unrated},
+			{Id: "black", OldRating: 1500, NewRating: 1500, Team: 1, Unrated: unrated},
+		},
+		StartedAt: endedAt.Add(-10 * time.Minute),
+		EndedAt:   endedAt,
+		Results:   results,
+	}
+}
+
+func TestReplaySkipsUnratedRecords(t *testing.T) {
+	replayer := NewReplayer(rating.DefaultConfig, replayAll)
+	if err := replayer.Replay(testRecord(true, 0.5, 0.5)); err != nil {
+		t.Fatal(err)
+	}
+	if states := replayer.States(); len(states) != 0 {
+		t.Errorf("unrated record replayed: %+v", states)
+	}
+}
+
+func TestReplayRatesRatedRecords(t *testing.T) {
+	replayer := NewReplayer(rating.DefaultConfig, replayAll)
+	if err := replayer.Replay(testRecord(false, 1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	states := replayer.States()
+	category, _ := entities.RatingCategoryOf("10+0")
+	white, black := states[Key{"white", category}], states[Key{"black", category}]
+	if len(white.History) != 1 || len(black.History) != 1 {
+		t.Fatalf("history = %d/%d entries, want 1/1", len(white.History), len(black.History))
+	}
+	if white.Rating.Rating <= black.Rating.Rating {
+		t.Errorf("ratings = %.2f/%.2f, want the winner above the loser", white.Rating.Rating, black.Rating.Rating)
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// Game modes with at least this much base time may be adjourned
+const minAdjournableDuration = 45 * time.Minute
+
+type adjournOfferResponse struct {
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type adjournedResponse struct {
+	Type     string   `json:"type"`
+	Clocks   []string `json:"clocks"`
+	Deadline string   `json:"deadline"`
+}
+
+// offerAdjourn method    records an adjournment proposal from the player's team
+func (m *Match) offerAdjourn(player *player) string {
+	if !m.cfg.Adjournable {
+		return ErrStatusAdjournNotAllowed
+	}
+	side := player.Side
+	m.adjournOffer = &side
+	m.sendAdjournOfferNotification(player, PENDING)
+	return ""
+}
+
+// acceptAdjourn method    adjourns the match if the opposing team proposed it
+func (m *Match) acceptAdjourn(player *player) string {
+	if m.adjournOffer == nil || *m.adjournOffer == player.Side {
+		return ErrStatusNoAdjournOffer
+	}
+	m.adjourn()
+	return ""
+}
+
+func (m *Match) declineAdjourn(player *player) {
+	if m.adjournOffer == nil || *m.adjournOffer == player.Side {
+		return
+	}
+	m.adjournOffer = nil
+	m.sendAdjournOfferNotification(player, DECLINED)
+}
+
+/*
+adjourn method    freezes both clocks and hands the match over to be persisted.
+The match is released from the server, players resume it later through matchRestore.
+*/
+func (m *Match) adjourn() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ended {
+		return
+	}
+	m.ended = true
+	close(m.done)
+
+	// Charge the team to move for the time spent on the current turn
+	currentTurnTeam := m.getCurrentTurnTeam()
+	if !m.cfg.Untimed && !currentTurnTeam.TurnStartedAt.IsZero() {
+		currentTurnTeam.Clock -= time.Since(currentTurnTeam.TurnStartedAt)
+	}
+	m.adjournedAt = time.Now()
+	m.skipTimer()
+
+	for _, player := range m.players {
+		err := player.writeJson(adjournedResponse{
+			Type:     "adjourned",
+			Clocks:   m.clocks(),
+			Deadline: m.adjournDeadline().Format(time.RFC3339),
+		})
+		if err != nil {
+			logging.Error(
+				"couldn't notify player about adjournment",
+				zap.String("player_id", player.Id),
+			)
+		}
+	}
+	m.disconnectPlayers("match adjourned", time.Now().Add(5*time.Second))
+	m.adjournGameHandler(m)
+}
+
+func (m *Match) adjournDeadline() time.Time {
+	return m.adjournedAt.Add(m.cfg.AdjournTimeout)
+}
+
+// adjournment method    returns everything needed to resume the match later
+func (m *Match) adjournment() entities.Adjournment {
+	adjournment := entities.Adjournment{
+		Moves:       make([]string, 0, len(m.game.moves)),
+		MoveClocks:  make([]string, 0, len(m.game.moves)),
+		Clocks:      make([]string, 0, len(m.teams)),
+		AdjournedAt: m.adjournedAt,
+		Deadline:    m.adjournDeadline(),
+	}
+	for _, move := range m.game.moves {
+		adjournment.Moves = append(adjournment.Moves, move.uci)
+		adjournment.MoveClocks = append(adjournment.MoveClocks, move.clock.String())
+	}
+	for _, team := range m.teams {
+		adjournment.Clocks = append(adjournment.Clocks, team.Clock.String())
+	}
+	return adjournment
+}
+
+func (m *Match) sendAdjournOfferNotification(sender *player, status string) {
+	for _, player := range m.players {
+		if player.Side == sender.Side {
+			continue
+		}
+		err := player.writeJson(adjournOfferResponse{
+			Type:      "adjournOffer",
+			Status:    status,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		})
+		if err != nil {
+			logging.Error(
+				"couldn't send adjourn offer notification to player: ",
+				zap.String("player_id", player.Id),
+			)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/chess-vn/slchess/internal/aws/auth"
+)
+
+const (
+	AUTH_PROVIDER_COGNITO = "cognito"
+	AUTH_PROVIDER_OIDC    = "oidc"
+	AUTH_PROVIDER_STATIC  = "static"
+	AUTH_PROVIDER_GUEST   = "guest"
+)
+
+var (
+	ErrNoAuthorization = errors.New("no authorization")
+	ErrUnknownProvider = errors.New("unknown auth provider")
+)
+
+// Identity struct    is the authenticated user behind a game socket
+type Identity struct {
+	UserId string
+	Guest  bool
+}
+
+/*
+Authenticator interface    resolves the user of an incoming websocket request.
+Implementations return ErrNoAuthorization when the request carries nothing they understand,
+so a chain of them can be tried in order.
+*/
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// jwtAuthenticator struct    validates RS256 tokens against the JWKS of an issuer
+type jwtAuthenticator struct {
+	validator *auth.Validator
+}
+
+// staticAuthenticator struct    maps fixed dev tokens to user ids, never use it in production
+type staticAuthenticator struct {
+	tokens map[string]string
+	// trustPlayerIdQuery accepts any ?playerId= as is, for load tests
+	trustPlayerIdQuery bool
+}
+
+// guestAuthenticator struct    validates the HMAC tokens handed to guest sessions
+type guestAuthenticator struct {
+	secret []byte
+}
+
+type chainAuthenticator []Authenticator
+
+// newAuthenticator function    builds the authenticator chain listed in the config
+func newAuthenticator(cfg Config) (Authenticator, error) {
+	chain := chainAuthenticator{}
+	for _, provider := range cfg.AuthProviders {
+		switch strings.ToLower(strings.TrimSpace(provider)) {
+		case AUTH_PROVIDER_COGNITO:
+			issuer := auth.CognitoIssuer(cfg.AwsRegion, cfg.CognitoUserPoolId)
+			source := keySourceOf(cfg, issuer+"/.well-known/jwks.json")
+			chain = append(chain, &jwtAuthenticator{
+				validator: auth.NewValidator(
+					auth.NewKeySet(source, auth.DefaultKeySetTTL),
+					auth.ValidatorConfig{
+						Issuer:    issuer,
+						Audience:  cfg.CognitoClientId,
+						TokenUses: cfg.CognitoTokenUses,
+					},
+				),
+			})
+		case AUTH_PROVIDER_OIDC:
+			jwksUrl := cfg.OidcJwksUrl
+			if jwksUrl == "" && cfg.JwksFile == "" {
+				var err error
+				jwksUrl, err = discoverJwksUrl(cfg.OidcIssuer)
+				if err != nil {
+					return nil, fmt.Errorf("failed to discover oidc keys: %w", err)
+				}
+			}
+			chain = append(chain, &jwtAuthenticator{
+				validator: auth.NewValidator(
+					auth.NewKeySet(keySourceOf(cfg, jwksUrl), auth.DefaultKeySetTTL),
+					auth.ValidatorConfig{
+						Issuer:   cfg.OidcIssuer,
+						Audience: cfg.OidcAudience,
+					},
+				),
+			})
+		case AUTH_PROVIDER_STATIC:
+			chain = append(chain, &staticAuthenticator{
+				tokens:             cfg.StaticTokens,
+				trustPlayerIdQuery: cfg.TrustPlayerIdQuery,
+			})
+		case AUTH_PROVIDER_GUEST:
+			if len(cfg.GuestTokenSecret) == 0 {
+				return nil, auth.ErrGuestTokenSecretNotSet
+			}
+			chain = append(chain, &guestAuthenticator{
+				secret: cfg.GuestTokenSecret,
+			})
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no auth provider configured")
+	}
+	return chain, nil
+}
+
+// keySourceOf function    prefers the configured JWKS file over the issuer endpoint
+func keySourceOf(cfg Config, jwksUrl string) auth.KeySource {
+	if cfg.JwksFile != "" {
+		return auth.NewFileKeySource(cfg.JwksFile)
+	}
+	return auth.NewUrlKeySource(jwksUrl)
+}
+
+// discoverJwksUrl function    reads the jwks_uri from the issuer's openid configuration
+func discoverJwksUrl(issuer string) (string, error) {
+	resp, err := http.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var config struct {
+		JwksUri string `json:"jwks_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return "", fmt.Errorf("failed to decode openid configuration: %w", err)
+	}
+	if config.JwksUri == "" {
+		return "", fmt.Errorf("missing jwks_uri")
+	}
+	return config.JwksUri, nil
+}
+
+// bearerToken function    returns the Authorization header without its Bearer scheme
+func bearerToken(r *http.Request) string {
+	token := r.Header.Get("Authorization")
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		return token[7:]
+	}
+	return token
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return Identity{}, ErrNoAuthorization
+	}
+	claims, err := a.validator.Validate(token)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{UserId: claims.Subject}, nil
+}
+
+func (a *staticAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	if userId, ok := a.tokens[bearerToken(r)]; ok {
+		return Identity{UserId: userId}, nil
+	}
+	if playerId := r.URL.Query().Get("playerId"); a.trustPlayerIdQuery && playerId != "" {
+		return Identity{UserId: playerId}, nil
+	}
+	return Identity{}, ErrNoAuthorization
+}
+
+func (a *guestAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return Identity{}, ErrNoAuthorization
+	}
+	guestId, err := auth.ParseGuestToken(token, a.secret)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{UserId: guestId, Guest: true}, nil
+}
+
+// Authenticate method    returns the first identity accepted by the chain
+func (c chainAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	errs := make([]error, 0, len(c))
+	for _, authenticator := range c {
+		identity, err := authenticator.Authenticate(r)
+		if err == nil {
+			return identity, nil
+		}
+		errs = append(errs, err)
+	}
+	return Identity{}, errors.Join(errs...)
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	QUICK_GOOD_GAME   = "GOOD_GAME"
+	QUICK_GOOD_LUCK   = "GOOD_LUCK"
+	QUICK_HAVE_FUN    = "HAVE_FUN"
+	QUICK_THANKS      = "THANKS"
+	QUICK_WELL_PLAYED = "WELL_PLAYED"
+)
+
+var quickMessages = map[string]string{
+	QUICK_GOOD_GAME:   "Good game!",
+	QUICK_GOOD_LUCK:   "Good luck!",
+	QUICK_HAVE_FUN:    "Have fun!",
+	QUICK_THANKS:      "Thanks!",
+	QUICK_WELL_PLAYED: "Well played!",
+}
+
+/*
+ChatFilter interface    checks player chat before it is delivered.
+Filter returns the text to deliver, or false to drop the message altogether.
+*/
+type ChatFilter interface {
+	Filter(content string) (string, bool)
+}
+
+// wordChatFilter struct    masks every banned word with asterisks
+type wordChatFilter struct {
+	pattern *regexp.Regexp
+}
+
+type chatMessage struct {
+	playerId  string
+	username  string
+	content   string
+	quick     string
+	createdAt time.Time
+}
+
+type chatMessageResponse struct {
+	Type      string `json:"type"`
+	PlayerId  string `json:"playerId"`
+	Username  string `json:"username"`
+	Content   string `json:"content"`
+	Quick     string `json:"quick,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type chatMuteResponse struct {
+	Type     string   `json:"type"`
+	MutedIds []string `json:"mutedIds"`
+}
+
+// chatLog struct    keeps the chat of a match so it can be archived with the record
+type chatLog struct {
+	messages []chatMessage
+	mu       sync.Mutex
+}
+
+// NewWordChatFilter function    builds a filter masking the given words, case insensitive
+func NewWordChatFilter(words []string) ChatFilter {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return wordChatFilter{}
+	}
+	return wordChatFilter{
+		pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`),
+	}
+}
+
+func (f wordChatFilter) Filter(content string) (string, bool) {
+	if f.pattern == nil {
+		return content, true
+	}
+	return f.pattern.ReplaceAllStringFunc(content, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	}), true
+}
+
+func (l *chatLog) append(msg chatMessage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+}
+
+func (l *chatLog) records() []dtos.ChatMessageRecordRequest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	records := make([]dtos.ChatMessageRecordRequest, 0, len(l.messages))
+	for _, msg := range l.messages {
+		records = append(records, dtos.ChatMessageRecordRequest{
+			PlayerId:  msg.playerId,
+			Username:  msg.username,
+			Content:   msg.content,
+			Quick:     msg.quick,
+			CreatedAt: msg.createdAt,
+		})
+	}
+	return records
+}
+
+/*
+sendChatMessage method    delivers a player chat message to every player of the match.
+Quick messages are looked up by their code and skip the text filter,
+players who muted the sender do not receive anything.
+*/
+func (m *Match) sendChatMessage(senderId, content, quick string) {
+	sender, exist := m.getPlayerWithId(senderId)
+	if !exist {
+		return
+	}
+	if quick != "" {
+		text, ok := quickMessages[strings.ToUpper(quick)]
+		if !ok {
+			sender.writeJson(errorResponse{
+				Type:  "error",
+				Error: ErrStatusInvalidQuickMessage,
+			})
+			return
+		}
+		quick, content = strings.ToUpper(quick), text
+	} else {
+		content = strings.TrimSpace(content)
+		if content == "" || utf8.RuneCountInString(content) > m.cfg.ChatMaxLength {
+			sender.writeJson(errorResponse{
+				Type:  "error",
+				Error: ErrStatusInvalidChatMessage,
+			})
+			return
+		}
+	}
+	if !sender.allowChat(m.cfg.ChatRateLimit, m.cfg.ChatRateWindow) {
+		sender.writeJson(errorResponse{
+			Type:  "error",
+			Error: ErrStatusChatRateLimited,
+		})
+		return
+	}
+	if quick == "" && m.chatFilter != nil {
+		filtered, ok := m.chatFilter.Filter(content)
+		if !ok {
+			sender.writeJson(errorResponse{
+				Type:  "error",
+				Error: ErrStatusChatFiltered,
+			})
+			return
+		}
+		content = filtered
+	}
+
+	msg := chatMessage{
+		playerId:  sender.Id,
+		username:  sender.Username,
+		content:   content,
+		quick:     quick,
+		createdAt: time.Now(),
+	}
+	m.chat.append(msg)
+	for _, player := range m.players {
+		if player.hasMuted(sender.Id) {
+			continue
+		}
+		err := player.writeJson(chatMessageResponse{
+			Type:      "chat",
+			PlayerId:  msg.playerId,
+			Username:  msg.username,
+			Content:   msg.content,
+			Quick:     msg.quick,
+			CreatedAt: msg.createdAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			logging.Error(
+				"couldn't send chat message to player: ",
+				zap.String("player_id", player.Id),
+			)
+		}
+	}
+}
+
+// setChatMute method    mutes or unmutes the chat of another player of the match
+func (m *Match) setChatMute(playerId, targetId string, muted bool) {
+	player, exist := m.getPlayerWithId(playerId)
+	if !exist {
+		return
+	}
+	if _, exist := m.getPlayerWithId(targetId); !exist || targetId == playerId {
+		player.writeJson(errorResponse{
+			Type:  "error",
+			Error: ErrStatusInvalidPlayerId,
+		})
+		return
+	}
+	player.writeJson(chatMuteResponse{
+		Type:     "chatMute",
+		MutedIds: player.setMuted(targetId, muted),
+	})
+}
+
+// allowChat method    records a chat message unless the player exceeded the rate limit
+func (p *player) allowChat(limit int, window time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	recent := p.chatSentAt[:0]
+	for _, sentAt := range p.chatSentAt {
+		if now.Sub(sentAt) < window {
+			recent = append(recent, sentAt)
+		}
+	}
+	p.chatSentAt = recent
+	if limit > 0 && len(p.chatSentAt) >= limit {
+		return false
+	}
+	p.chatSentAt = append(p.chatSentAt, now)
+	return true
+}
+
+// setMuted method    returns the ids muted by the player after the change
+func (p *player) setMuted(playerId string, muted bool) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if muted {
+		p.mutedIds[playerId] = true
+	} else {
+		delete(p.mutedIds, playerId)
+	}
+	mutedIds := make([]string, 0, len(p.mutedIds))
+	for id := range p.mutedIds {
+		mutedIds = append(mutedIds, id)
+	}
+	return mutedIds
+}
+
+func (p *player) hasMuted(playerId string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mutedIds[playerId]
+}
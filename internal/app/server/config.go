@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -18,14 +19,32 @@ import (
 type Config struct {
 	Port        string
 	IdleTimeout time.Duration
+	// How long a dropped socket may come back with its resume token
+	ResumeTokenTTL time.Duration
 
 	AwsRegion            string
 	CognitoUserPoolId    string
+	CognitoClientId      string
+	CognitoTokenUses     []string
 	AppSyncHttpUrl       string
 	AppSyncAccessRoleArn string
 	AbortGameFunctionArn string
 	EndGameFunctionArn   string
 	MaxMatches           int32
+	ChatBannedWords      []string
+	MatchTicketSecret    []byte
+	// Load test servers play matches without invoking the game lambdas or saving states
+	PersistMatches bool
+
+	AuthProviders      []string
+	StaticTokens       map[string]string
+	TrustPlayerIdQuery bool
+	OidcIssuer         string
+	OidcAudience       string
+	OidcJwksUrl        string
+	// Read the JWKS from this file instead of the issuer, for local and offline runs
+	JwksFile         string
+	GuestTokenSecret []byte
 
 	AwsCfg aws.Config
 }
@@ -66,8 +85,17 @@ func NewConfig() Config {
 		logging.Fatal("fatal error config file", zap.Error(err))
 	}
 	cfg.IdleTimeout = idleTimeout
+	viper.SetDefault("Server.ResumeTokenTTL", "2m")
+	resumeTokenTTL, err := time.ParseDuration(viper.GetString("Server.ResumeTokenTTL"))
+	if err != nil {
+		logging.Fatal("fatal error config file", zap.Error(err))
+	}
+	cfg.ResumeTokenTTL = resumeTokenTTL
 	cfg.AwsRegion = viper.GetString("AWS_REGION")
 	cfg.CognitoUserPoolId = viper.GetString("COGNITO_USER_POOL_ID")
+	cfg.CognitoClientId = viper.GetString("COGNITO_USER_POOL_CLIENT_ID")
+	viper.SetDefault("COGNITO_TOKEN_USE", "id,access")
+	cfg.CognitoTokenUses = strings.Split(viper.GetString("COGNITO_TOKEN_USE"), ",")
 	cfg.AppSyncHttpUrl = viper.GetString("APPSYNC_HTTP_URL")
 	cfg.AppSyncAccessRoleArn = viper.GetString("APPSYNC_ACCESS_ROLE_ARN")
 	cfg.AbortGameFunctionArn = viper.GetString("ABORT_GAME_FUNCTION_ARN")
@@ -75,6 +103,20 @@ func NewConfig() Config {
 
 	viper.SetDefault("MAX_MATCHES", 100)
 	cfg.MaxMatches = viper.GetInt32("MAX_MATCHES")
+	cfg.ChatBannedWords = viper.GetStringSlice("Chat.BannedWords")
+	cfg.MatchTicketSecret = []byte(viper.GetString("MATCH_TICKET_SECRET"))
+	viper.SetDefault("PERSIST_MATCHES", true)
+	cfg.PersistMatches = viper.GetBool("PERSIST_MATCHES")
+
+	viper.SetDefault("AUTH_PROVIDERS", AUTH_PROVIDER_COGNITO)
+	cfg.AuthProviders = strings.Split(viper.GetString("AUTH_PROVIDERS"), ",")
+	cfg.StaticTokens = parseStaticTokens(viper.GetString("AUTH_STATIC_TOKENS"))
+	cfg.TrustPlayerIdQuery = viper.GetBool("AUTH_TRUST_PLAYER_ID_QUERY")
+	cfg.OidcIssuer = viper.GetString("OIDC_ISSUER")
+	cfg.OidcAudience = viper.GetString("OIDC_AUDIENCE")
+	cfg.OidcJwksUrl = viper.GetString("OIDC_JWKS_URL")
+	cfg.JwksFile = viper.GetString("AUTH_JWKS_FILE")
+	cfg.GuestTokenSecret = []byte(viper.GetString("GUEST_TOKEN_SECRET"))
 
 	if err := cfg.loadAwsConfig(); err != nil {
 		logging.Fatal("failed to load aws config: %w", zap.Error(err))
@@ -83,6 +125,18 @@ func NewConfig() Config {
 	return cfg
 }
 
+// parseStaticTokens function    reads dev tokens written as token=userId pairs separated by commas
+func parseStaticTokens(value string) map[string]string {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		token, userId, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if found && token != "" && userId != "" {
+			tokens[token] = userId
+		}
+	}
+	return tokens
+}
+
 func loadEnvFiles(filenames []string) error {
 	for _, file := range filenames {
 		viper.SetConfigFile(file)
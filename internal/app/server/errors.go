@@ -4,12 +4,30 @@ import "errors"
 
 var (
 	ErrStatusInvalidMove     string = "INVALID_MOVE"
+	ErrStatusAmbiguousMove   string = "AMBIGUOUS_MOVE"
+	ErrStatusUnknownNotation string = "UNKNOWN_NOTATION"
 	ErrStatusInvalidPlayerId string = "INVALID_PLAYER_ID"
 	ErrStatusWrongTurn       string = "WRONG_TURN"
 	ErrStatusAbortInvalidPly string = "INVALID_PLY"
+
+	ErrStatusWrongRole        string = "WRONG_ROLE"
+	ErrStatusInvalidPiece     string = "INVALID_PIECE"
+	ErrStatusPieceNotSelected string = "PIECE_NOT_SELECTED"
+	ErrStatusWrongPiece       string = "WRONG_PIECE"
+
+	ErrStatusNotPrimaryDevice string = "NOT_PRIMARY_DEVICE"
+
+	ErrStatusAdjournNotAllowed string = "ADJOURN_NOT_ALLOWED"
+	ErrStatusNoAdjournOffer    string = "NO_ADJOURN_OFFER"
+
+	ErrStatusInvalidChatMessage  string = "INVALID_CHAT_MESSAGE"
+	ErrStatusInvalidQuickMessage string = "INVALID_QUICK_MESSAGE"
+	ErrStatusChatRateLimited     string = "CHAT_RATE_LIMITED"
+	ErrStatusChatFiltered        string = "CHAT_FILTERED"
 )
 
 var (
 	ErrFailedToLoadMatch = errors.New("failed to load match")
 	ErrInvalidOutcome    = errors.New("invalid outcome")
+	ErrAdjournExpired    = errors.New("adjournment expired")
 )
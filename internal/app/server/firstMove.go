@@ -0,0 +1,106 @@
+package server
+
+import (
+	"time"
+
+	"github.com/chess-vn/slchess/pkg/logging"
+	"github.com/notnil/chess"
+	"go.uber.org/zap"
+)
+
+type firstMoveCountdownResponse struct {
+	Type     string `json:"type"`
+	Side     string `json:"side"`
+	Deadline string `json:"deadline"`
+}
+
+/*
+usesFirstMoveCountdown method    reports whether the side to move is on its first move countdown.
+Clocks only start once a side has made its first move, simul boards keep the regular clock.
+*/
+func (m *Match) usesFirstMoveCountdown() bool {
+	return m.simul == nil && m.game.plies() < 2
+}
+
+// canAbort method    a player may abort until their own side has made its first move
+func (m *Match) canAbort(player *player) bool {
+	if player.Side == WHITE_SIDE {
+		return m.game.plies() < 1
+	}
+	return m.game.plies() < 2
+}
+
+// startTurnTimer method    starts the turn of the team to move and arms the match timer
+func (m *Match) startTurnTimer() {
+	team := m.getCurrentTurnTeam()
+	team.TurnStartedAt = time.Now()
+	if !m.usesFirstMoveCountdown() {
+		m.firstMoveDeadline = time.Time{}
+		m.setTimer(m.turnTimeout(team))
+		return
+	}
+	m.firstMoveDeadline = team.TurnStartedAt.Add(m.cfg.FirstMoveTimeout)
+	m.setTimer(m.cfg.FirstMoveTimeout)
+	for _, player := range m.players {
+		m.sendFirstMoveCountdown(player)
+	}
+}
+
+func (m *Match) sendFirstMoveCountdown(player *player) {
+	if m.firstMoveDeadline.IsZero() {
+		return
+	}
+	side := "WHITE"
+	if m.getCurrentTurnTeam().Side == BLACK_SIDE {
+		side = "BLACK"
+	}
+	err := player.writeJson(firstMoveCountdownResponse{
+		Type:     "firstMoveCountdown",
+		Side:     side,
+		Deadline: m.firstMoveDeadline.Format(time.RFC3339),
+	})
+	if err != nil {
+		logging.Error(
+			"couldn't send first move countdown to player: ",
+			zap.String("player_id", player.Id),
+		)
+	}
+}
+
+/*
+handleTimeout method    is called when the match timer fires.
+A side that misses its first move makes the match a no-contest abort.
+*/
+func (m *Match) handleTimeout() {
+	if m.isEnded() {
+		return
+	}
+	if !m.resumedAdjournment &&
+		m.usesFirstMoveCountdown() &&
+		m.game.outcome() == chess.NoOutcome {
+		side := m.getCurrentTurnTeam().Side
+		m.noShowSide = &side
+		logging.Info(
+			"first move missed",
+			zap.String("match_id", m.id),
+			zap.Bool("white", side == WHITE_SIDE),
+		)
+		m.abort()
+		return
+	}
+	m.end()
+}
+
+// noShowPlayerIds method    returns the players whose side missed its first move
+func (m *Match) noShowPlayerIds() []string {
+	if m.noShowSide == nil {
+		return nil
+	}
+	ids := []string{}
+	for _, player := range m.players {
+		if player.Side == *m.noShowSide {
+			ids = append(ids, player.Id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,42 @@
+package server
+
+import "testing"
+
+// Position after 1. e4 e5 2. Nf3 Nc6 ... 10. O-O O-O, white to move
+const move10Fen = "r1bq1rk1/pppp1ppp/2n2n2/2b1p3/2B1P3/2NP1N2/PPP2PPP/R1BQ1RK1 w - - 0 11"
+
+func TestRestoredGameKeepsItsPly(t *testing.T) {
+	g, err := restoreGame(move10Fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.plies(); got != 20 {
+		t.Errorf("plies = %d, want 20", got)
+	}
+}
+
+func TestRestoredGameCannotBeAborted(t *testing.T) {
+	g, err := restoreGame(move10Fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	match := &Match{game: g}
+	if match.usesFirstMoveCountdown() {
+		t.Error("restored game uses the first move countdown")
+	}
+	for _, side := range []Side{WHITE_SIDE, BLACK_SIDE} {
+		if match.canAbort(&player{Side: side}) {
+			t.Errorf("abort allowed for side %v of a restored game", side)
+		}
+	}
+}
+
+func TestNewGameCanBeAbortedBeforeTheFirstMove(t *testing.T) {
+	match := &Match{game: newGame()}
+	if !match.usesFirstMoveCountdown() {
+		t.Error("new game does not use the first move countdown")
+	}
+	if !match.canAbort(&player{Side: WHITE_SIDE}) {
+		t.Error("abort rejected before the first move")
+	}
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/notnil/chess"
@@ -25,6 +27,11 @@ const (
 	RESIGN
 	OFFER_DRAW
 	DECLINE_DRAW
+	SELECT_PIECE
+	OFFER_ADJOURN
+	ACCEPT_ADJOURN
+	DECLINE_ADJOURN
+	FLAG
 	NONE
 
 	BLACK_OUT_OF_TIME        = "BLACK_OUT_OF_TIME"
@@ -32,6 +39,7 @@ const (
 	BLACK_DISCONNECT_TIMEOUT = "BLACK_DISCONNECT_TIMEOUT"
 	WHITE_DISCONNECT_TIMEOUT = "WHITE_DISCONNECT_TIMEOUT"
 	DRAW_BY_TIMEOUT          = "DRAW_BY_TIMEOUT"
+	ADJOURNMENT_EXPIRED      = "ADJOURNMENT_EXPIRED"
 )
 
 type drawOffer struct {
@@ -44,6 +52,8 @@ type game struct {
 	customOutcome chess.Outcome
 	drawOffer     *drawOffer
 	moves         []move
+	// Plies played before the position the game was restored from
+	startPly int
 }
 
 func newGame() *game {
@@ -70,9 +80,59 @@ func restoreGame(gameState string) (*game, error) {
 		Game:      *g,
 		drawOffer: nil,
 		moves:     []move{},
+		startPly:  fenPly(gameState),
 	}, nil
 }
 
+// fenPly function    returns the plies played before a FEN position, from its fullmove number and side to move
+func fenPly(fen string) int {
+	fields := strings.Fields(fen)
+	if len(fields) < 6 {
+		return 0
+	}
+	fullmove, err := strconv.Atoi(fields[5])
+	if err != nil || fullmove < 1 {
+		return 0
+	}
+	ply := 2 * (fullmove - 1)
+	if fields[1] == "b" {
+		ply++
+	}
+	return ply
+}
+
+// plies method    returns every ply of the game, including those played before it was restored
+func (g *game) plies() int {
+	return g.startPly + len(g.moves)
+}
+
+// recordClock method    attaches the moving team's clock to the last move
+func (g *game) recordClock(clock time.Duration) {
+	if length := len(g.moves); length > 0 {
+		g.moves[length-1].clock = clock
+	}
+}
+
+// recordTiming method    attaches the time spent and the clock adjustments to the last move
+func (g *game) recordTiming(timeTaken, lagForgiven, increment time.Duration) {
+	if length := len(g.moves); length > 0 {
+		g.moves[length-1].timeTaken = timeTaken
+		g.moves[length-1].lagForgiven = lagForgiven
+		g.moves[length-1].increment = increment
+	}
+}
+
+// restoreGameFromMoves function    replays the uci moves so the full history is kept
+func restoreGameFromMoves(moves []string) (*game, error) {
+	g := newGame()
+	for _, uci := range moves {
+		if err := g.move(move{uci: uci}); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
 func (g *game) OfferDraw(side chess.Color) bool {
 	fmt.Println(side)
 	if g.drawOffer != nil && g.drawOffer.Side != side &&
@@ -115,6 +175,11 @@ func (g *game) drawByTimeout() {
 	g.customOutcome = DRAW_BY_TIMEOUT
 }
 
+// adjournmentExpired method    scores an adjourned game nobody resumed in time as a draw
+func (g *game) adjournmentExpired() {
+	g.customOutcome = ADJOURNMENT_EXPIRED
+}
+
 func (g *game) outcome() chess.Outcome {
 	switch g.customOutcome {
 	case BLACK_OUT_OF_TIME:
@@ -125,7 +190,7 @@ func (g *game) outcome() chess.Outcome {
 		return chess.WhiteWon
 	case WHITE_DISCONNECT_TIMEOUT:
 		return chess.BlackWon
-	case DRAW_BY_TIMEOUT:
+	case DRAW_BY_TIMEOUT, ADJOURNMENT_EXPIRED:
 		return chess.Draw
 	default:
 		return g.Outcome()
@@ -138,6 +203,10 @@ func (g *game) method() string {
 		return "OUT_OF_TIME"
 	case WHITE_DISCONNECT_TIMEOUT, BLACK_DISCONNECT_TIMEOUT:
 		return "DISCONNECT_TIMEOUT"
+	case DRAW_BY_TIMEOUT:
+		return DRAW_BY_TIMEOUT
+	case ADJOURNMENT_EXPIRED:
+		return ADJOURNMENT_EXPIRED
 	default:
 		return g.Method().String()
 	}
@@ -158,11 +227,31 @@ func (g *game) move(move move) error {
 	return nil
 }
 
+// pieceTypeOf method    returns the type of the piece moved by the given uci move
+func (g *game) pieceTypeOf(uci string) (chess.PieceType, error) {
+	pos := g.Position()
+	m, err := chess.UCINotation{}.Decode(pos, uci)
+	if err != nil {
+		return chess.NoPieceType, err
+	}
+	return pos.Board().Piece(m.S1()).Type(), nil
+}
+
 type move struct {
 	playerId  string
 	uci       string
+	notation  string
+	piece     string
 	control   GameControl
 	createdAt time.Time
+	// Server time at which the move arrived
+	receivedAt time.Time
+	// Clock of the moving team right after the move
+	clock time.Duration
+	// Timing charged against the clock for this ply
+	timeTaken   time.Duration
+	lagForgiven time.Duration
+	increment   time.Duration
 }
 
 func (s Status) String() string {
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/notnil/chess"
+)
+
+/*
+gameStateVersion is the current format of the game state message.
+Version 1 clients only get outcome, method, fen, clocks and statuses.
+*/
+const (
+	legacyGameStateVersion = 1
+	gameStateVersion       = 2
+)
+
+type moveResponse struct {
+	Uci string `json:"uci"`
+	San string `json:"san"`
+}
+
+type capturedMaterialResponse struct {
+	// Pieces captured by each side, as lowercase piece letters
+	White []string `json:"white"`
+	Black []string `json:"black"`
+	// Material balance in pawns, positive when white is ahead
+	Balance int `json:"balance"`
+}
+
+// Version 1 layout of the game state, kept for old clients
+type legacyGameStateResponse struct {
+	Outcome  string   `json:"outcome"`
+	Method   string   `json:"method"`
+	Fen      string   `json:"fen"`
+	Clocks   []string `json:"clocks"`
+	Statuses []string `json:"statuses,omitempty"`
+}
+
+type legacyMatchResponse struct {
+	Type      string                  `json:"type"`
+	GameState legacyGameStateResponse `json:"game"`
+}
+
+// versionedMessage is implemented by messages whose format depends on the client version
+type versionedMessage interface {
+	forVersion(version int) interface{}
+}
+
+func (r matchResponse) forVersion(version int) interface{} {
+	if version < gameStateVersion {
+		return legacyMatchResponse{
+			Type:      r.Type,
+			GameState: r.GameState.legacy(),
+		}
+	}
+	return r
+}
+
+// gameStateVersionOf function    reads the game state version requested by the client, old clients send none
+func gameStateVersionOf(r *http.Request) int {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil || version < legacyGameStateVersion {
+		return legacyGameStateVersion
+	}
+	return min(version, gameStateVersion)
+}
+
+// legacy method    strips every field introduced after version 1
+func (r gameStateResponse) legacy() legacyGameStateResponse {
+	return legacyGameStateResponse{
+		Outcome:  r.Outcome,
+		Method:   r.Method,
+		Fen:      r.Fen,
+		Clocks:   r.Clocks,
+		Statuses: r.Statuses,
+	}
+}
+
+/*
+gameState method    builds the game state broadcast to players.
+The full move list is only included on sync.
+*/
+func (m *Match) gameState(withMoves bool) gameStateResponse {
+	g := m.game
+	pos := g.Position()
+	resp := gameStateResponse{
+		Version:     gameStateVersion,
+		Outcome:     g.outcome().String(),
+		Method:      g.method(),
+		Fen:         g.FEN(),
+		Clocks:      m.clocks(),
+		Ply:         m.currentPly(),
+		Turn:        colorName(pos.Turn()),
+		LegalMoves:  []string{},
+		Captured:    capturedMaterial(pos.Board()),
+		Repetitions: g.repetitions(),
+	}
+	moves := g.Moves()
+	if len(moves) > 0 {
+		lastMove := moves[len(moves)-1]
+		resp.Check = lastMove.HasTag(chess.Check)
+		resp.LastMove = &moveResponse{
+			Uci: chess.UCINotation{}.Encode(nil, lastMove),
+			San: g.san(len(moves) - 1),
+		}
+	}
+	if g.outcome() == chess.NoOutcome {
+		for _, move := range g.ValidMoves() {
+			resp.LegalMoves = append(resp.LegalMoves, chess.UCINotation{}.Encode(nil, move))
+		}
+	}
+	if withMoves {
+		resp.Moves = make([]moveResponse, 0, len(moves))
+		for i, move := range moves {
+			resp.Moves = append(resp.Moves, moveResponse{
+				Uci: chess.UCINotation{}.Encode(nil, move),
+				San: g.san(i),
+			})
+		}
+	}
+	return resp
+}
+
+// san method    returns the i-th move of the game in standard algebraic notation
+func (g *game) san(i int) string {
+	return chess.AlgebraicNotation{}.Encode(g.Positions()[i], g.Moves()[i])
+}
+
+// repetitions method    returns how many times the current position has occurred
+func (g *game) repetitions() int {
+	current := g.Position()
+	count := 0
+	for _, pos := range g.Positions() {
+		if pos.Board().String() == current.Board().String() &&
+			pos.Turn() == current.Turn() &&
+			pos.CastleRights() == current.CastleRights() &&
+			pos.EnPassantSquare() == current.EnPassantSquare() {
+			count++
+		}
+	}
+	return count
+}
+
+var (
+	pieceValues = map[chess.PieceType]int{
+		chess.Queen:  9,
+		chess.Rook:   5,
+		chess.Bishop: 3,
+		chess.Knight: 3,
+		chess.Pawn:   1,
+	}
+	startingPieces = map[chess.PieceType]int{
+		chess.Queen:  1,
+		chess.Rook:   2,
+		chess.Bishop: 2,
+		chess.Knight: 2,
+		chess.Pawn:   8,
+	}
+	capturableTypes = []chess.PieceType{
+		chess.Queen,
+		chess.Rook,
+		chess.Bishop,
+		chess.Knight,
+		chess.Pawn,
+	}
+)
+
+// capturedMaterial function    compares the board with the starting material of each side
+func capturedMaterial(board *chess.Board) *capturedMaterialResponse {
+	counts := map[chess.Color]map[chess.PieceType]int{
+		chess.White: {},
+		chess.Black: {},
+	}
+	for _, piece := range board.SquareMap() {
+		counts[piece.Color()][piece.Type()]++
+	}
+	resp := &capturedMaterialResponse{
+		White: []string{},
+		Black: []string{},
+	}
+	for _, pieceType := range capturableTypes {
+		// Pieces missing from black were captured by white and vice versa
+		for range max(startingPieces[pieceType]-counts[chess.Black][pieceType], 0) {
+			resp.White = append(resp.White, pieceType.String())
+		}
+		for range max(startingPieces[pieceType]-counts[chess.White][pieceType], 0) {
+			resp.Black = append(resp.Black, pieceType.String())
+		}
+		resp.Balance += pieceValues[pieceType] *
+			(counts[chess.White][pieceType] - counts[chess.Black][pieceType])
+	}
+	return resp
+}
+
+func colorName(color chess.Color) string {
+	if color == chess.White {
+		return "WHITE"
+	}
+	return "BLACK"
+}
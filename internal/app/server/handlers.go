@@ -17,10 +17,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/chess-vn/slchess/internal/aws/storage"
 	"github.com/chess-vn/slchess/internal/domains/dtos"
+	"github.com/chess-vn/slchess/internal/domains/entities"
 	"github.com/chess-vn/slchess/pkg/logging"
 	"github.com/chess-vn/slchess/pkg/utils"
-	"github.com/gorilla/websocket"
-	"github.com/notnil/chess"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +27,11 @@ func (s *server) handleAbortGame(match *Match) {
 	if match == nil {
 		return
 	}
+	if !s.cfg.PersistMatches {
+		s.removeMatch(match.id)
+		logging.Info("match aborted", zap.String("match_id", match.id))
+		return
+	}
 	ctx := context.TODO()
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
@@ -36,12 +40,13 @@ func (s *server) handleAbortGame(match *Match) {
 	lambdaClient := lambda.NewFromConfig(cfg)
 
 	matchAbortReq := dtos.MatchAbortRequest{
-		MatchId: match.id,
-		PlayerIds: []string{
-			match.players[0].Id,
-			match.players[1].Id,
-		},
+		MatchId:   match.id,
+		PlayerIds: make([]string, 0, len(match.players)),
 	}
+	for _, player := range match.players {
+		matchAbortReq.PlayerIds = append(matchAbortReq.PlayerIds, player.Id)
+	}
+	matchAbortReq.NoShowPlayerIds = match.noShowPlayerIds()
 
 	payload, err := json.Marshal(matchAbortReq)
 	if err != nil {
@@ -66,29 +71,37 @@ func (s *server) handleAbortGame(match *Match) {
 
 // Handler for saving current game state.
 func (s *server) handleSaveGame(match *Match) {
+	if !s.cfg.PersistMatches {
+		return
+	}
 	ctx := context.Background()
 	lastMove := match.game.lastMove()
 	matchStateReq := dtos.MatchStateRequest{
-		Id:      utils.GenerateUUID(),
-		MatchId: match.id,
-		PlayerStates: []dtos.PlayerStateRequest{
-			{
-				Clock:  match.players[0].Clock.String(),
-				Status: match.players[0].Status.String(),
-			},
-			{
-				Clock:  match.players[1].Clock.String(),
-				Status: match.players[1].Status.String(),
-			},
-		},
-		GameState: match.game.FEN(),
+		Id:           utils.GenerateUUID(),
+		MatchId:      match.id,
+		PlayerStates: make([]dtos.PlayerStateRequest, 0, len(match.teams)),
+		GameState:    match.game.FEN(),
 		Move: dtos.MoveRequest{
-			PlayerId: lastMove.playerId,
-			Uci:      lastMove.uci,
+			PlayerId:    lastMove.playerId,
+			Uci:         lastMove.uci,
+			TimeTaken:   lastMove.timeTaken.String(),
+			LagForgiven: lastMove.lagForgiven.String(),
+			Increment:   lastMove.increment.String(),
+			ReceivedAt:  lastMove.receivedAt,
 		},
 		Ply:       match.currentPly(),
 		Timestamp: time.Now(),
 	}
+	// One state per team, so team games keep the two-clock layout
+	for _, team := range match.teams {
+		matchStateReq.PlayerStates = append(
+			matchStateReq.PlayerStates,
+			dtos.PlayerStateRequest{
+				Clock:  match.teamClock(team).String(),
+				Status: team.status().String(),
+			},
+		)
+	}
 	matchStateAppSyncReq := dtos.NewMatchStateAppSyncRequest(matchStateReq)
 	payload, err := json.Marshal(matchStateAppSyncReq)
 	if err != nil {
@@ -153,42 +166,17 @@ func (s *server) handleEndGame(match *Match) {
 	if match == nil {
 		return
 	}
+	if !s.cfg.PersistMatches {
+		s.removeMatch(match.id)
+		logging.Info("match ended", zap.String("match_id", match.id))
+		return
+	}
 	ctx := context.TODO()
 
-	newRatings, newRDs, err := match.getNewPlayerRatings()
+	matchRecordReq, err := match.recordRequest(time.Now())
 	if err != nil {
 		logging.Fatal("failed to invoke end game", zap.Error(err))
 	}
-	matchRecordReq := dtos.MatchRecordRequest{
-		MatchId: match.id,
-		Players: []dtos.PlayerRecordRequest{
-			{
-				Id:        match.players[0].Id,
-				OldRating: match.players[0].Rating,
-				NewRating: newRatings[0],
-				OldRD:     match.players[0].RD,
-				NewRD:     newRDs[0],
-			},
-			{
-				Id:        match.players[1].Id,
-				OldRating: match.players[1].Rating,
-				NewRating: newRatings[1],
-				NewRD:     newRDs[1],
-				OldRD:     match.players[1].RD,
-			},
-		},
-		Pgn:       match.game.String(),
-		StartedAt: match.startAt,
-		EndedAt:   time.Now(),
-	}
-	switch match.game.outcome() {
-	case chess.WhiteWon:
-		matchRecordReq.Results = []float64{1.0, 0.0}
-	case chess.BlackWon:
-		matchRecordReq.Results = []float64{0.0, 1.0}
-	case chess.Draw, chess.NoOutcome:
-		matchRecordReq.Results = []float64{0.5, 0.5}
-	}
 	payload, err := json.Marshal(matchRecordReq)
 	if err != nil {
 		log.Fatal(err)
@@ -210,8 +198,68 @@ func (s *server) handleEndGame(match *Match) {
 	logging.Info("match ended", zap.String("match_id", match.id))
 }
 
+// Handler for when every board of a simul has finished
+func (s *server) handleSimulEnd(session *simulSession) {
+	err := s.storageClient.UpdateSimul(
+		context.Background(),
+		session.id,
+		storage.SimulUpdateOptions{
+			Status: aws.String(entities.SimulStatusEnded),
+		},
+	)
+	if err != nil {
+		logging.Error("failed to update simul", zap.Error(err))
+	}
+	s.simuls.Delete(session.id)
+	logging.Info("simul ended", zap.String("simul_id", session.id))
+}
+
+// Handler for when both players accept an adjournment
+func (s *server) handleAdjournGame(match *Match) {
+	adjournment := match.adjournment()
+	err := s.storageClient.UpdateActiveMatch(
+		context.Background(),
+		match.id,
+		storage.ActiveMatchUpdateOptions{
+			Adjournment: &adjournment,
+		},
+	)
+	if err != nil {
+		logging.Error("failed to persist adjournment", zap.Error(err))
+	}
+	s.removeMatch(match.id)
+	logging.Info(
+		"match adjourned",
+		zap.String("match_id", match.id),
+		zap.Time("deadline", adjournment.Deadline),
+	)
+}
+
+// resumeAdjournedMatch method    restarts the clocks once every team is back
+func (s *server) resumeAdjournedMatch(match *Match) {
+	match.resumedAdjournment = false
+	match.startAt = time.Now()
+	match.startTurnTimer()
+	err := s.storageClient.UpdateActiveMatch(
+		context.Background(),
+		match.id,
+		storage.ActiveMatchUpdateOptions{
+			StartedAt:        aws.Time(match.startAt),
+			ClearAdjournment: true,
+		},
+	)
+	if err != nil {
+		logging.Error("failed to clear adjournment", zap.Error(err))
+	}
+	logging.Info("adjourned match resumed", zap.String("match_id", match.id))
+}
+
 // Handler for when a user connection closes
-func (s *server) handlePlayerDisconnect(match *Match, playerId string) {
+func (s *server) handlePlayerDisconnect(
+	conn connection,
+	match *Match,
+	playerId string,
+) {
 	if match == nil {
 		return
 	}
@@ -221,12 +269,23 @@ func (s *server) handlePlayerDisconnect(match *Match, playerId string) {
 		logging.Fatal("invalid player id", zap.String("player_id", playerId))
 		return
 	}
-	player.setConn(nil)
+	player.removeConn(conn)
+
+	// The player is still connected from another device
+	if player.isConnected() {
+		logging.Info(
+			"player device disconnected",
+			zap.String("match_id", match.id),
+			zap.String("player_id", player.Id),
+		)
+		return
+	}
 
-	currentClock := match.getCurrentTurnPlayer().Clock
+	currentClock := match.turnTimeout(match.getCurrentTurnTeam())
 
-	// If both player disconnected, set the clock to current turn clock
-	if match.players[0].Status == match.players[1].Status {
+	// If both teams disconnected, set the clock to current turn clock
+	if match.teams[0].status() == DISCONNECTED &&
+		match.teams[1].status() == DISCONNECTED {
 		logging.Info(
 			"both player disconnected",
 			zap.String("match_id", match.id),
@@ -251,13 +310,14 @@ func (s *server) handlePlayerDisconnect(match *Match, playerId string) {
 		match.notifyAboutPlayerStatus(playerStatusResponse{
 			Type:     "playerStatus",
 			PlayerId: playerId,
-			Status:   player.Status.String(),
+			Status:   player.status().String(),
 		})
 	}
 }
 
 func (s *server) handlePlayerJoin(
-	conn *websocket.Conn,
+	conn connection,
+	version int,
 	match *Match,
 	playerId string,
 ) {
@@ -270,10 +330,10 @@ func (s *server) handlePlayerJoin(
 		logging.Fatal("invalid player id", zap.String("player_id", playerId))
 		return
 	}
-	if player.Status == INIT && player.Side == WHITE_SIDE {
+	if team := match.getTeamOf(player); team.status() == INIT &&
+		team.Side == WHITE_SIDE && !match.resumedAdjournment {
 		match.startAt = time.Now()
-		player.TurnStartedAt = match.startAt
-		match.setTimer(match.cfg.MatchDuration)
+		match.startTurnTimer()
 		err := s.storageClient.UpdateActiveMatch(
 			context.Background(),
 			match.id,
@@ -288,7 +348,12 @@ func (s *server) handlePlayerJoin(
 			)
 		}
 	}
-	player.setConn(conn)
+	player.addConn(conn, version)
+	if match.resumedAdjournment &&
+		match.teams[0].status() == CONNECTED &&
+		match.teams[1].status() == CONNECTED {
+		s.resumeAdjournedMatch(match)
+	}
 
 	match.syncPlayer(player)
 
@@ -300,12 +365,13 @@ func (s *server) handlePlayerJoin(
 	match.notifyAboutPlayerStatus(playerStatusResponse{
 		Type:     "playerStatus",
 		PlayerId: playerId,
-		Status:   player.Status.String(),
+		Status:   player.status().String(),
 	})
 }
 
 // Handler for when user sends a message
 func (s *server) handleWebSocketMessage(
+	conn connection,
 	playerId string,
 	match *Match,
 	payload payload,
@@ -323,6 +389,10 @@ func (s *server) handleWebSocketMessage(
 	}
 	switch payload.Type {
 	case "gameData":
+		// Only the primary device plays, controls included
+		if !s.checkPrimaryDevice(conn, match, playerId) {
+			return
+		}
 		action := payload.Data["action"]
 		switch action {
 		case "abort":
@@ -333,8 +403,21 @@ func (s *server) handleWebSocketMessage(
 			match.processGameControl(playerId, OFFER_DRAW)
 		case "declineDraw":
 			match.processGameControl(playerId, DECLINE_DRAW)
+		case "offerAdjourn":
+			match.processGameControl(playerId, OFFER_ADJOURN)
+		case "acceptAdjourn":
+			match.processGameControl(playerId, ACCEPT_ADJOURN)
+		case "declineAdjourn":
+			match.processGameControl(playerId, DECLINE_ADJOURN)
 		case "move":
-			match.processMove(playerId, payload.Data["move"], payload.CreatedAt)
+			match.processMove(
+				playerId,
+				payload.Data["move"],
+				payload.Data["notation"],
+				payload.CreatedAt,
+			)
+		case "selectPiece":
+			match.processPieceSelection(playerId, payload.Data["piece"])
 		default:
 			logging.Info("invalid game action:", zap.String("action", payload.Type))
 			return
@@ -346,7 +429,62 @@ func (s *server) handleWebSocketMessage(
 		)
 	case "sync":
 		match.syncPlayerWithId(playerId)
+	case "setPrimaryDevice":
+		if player, exist := match.getPlayerWithId(playerId); exist {
+			player.requestPrimary(conn)
+		}
+	case "confirmPrimaryDevice":
+		if player, exist := match.getPlayerWithId(playerId); exist && !player.confirmPrimary(conn) {
+			player.writeJsonTo(conn, errorResponse{
+				Type:  "error",
+				Error: ErrStatusNotPrimaryDevice,
+			})
+		}
+	case "teamChat":
+		match.sendTeamMessageWithId(playerId, payload.Data["message"])
+	case "chat":
+		match.sendChatMessage(playerId, payload.Data["message"], payload.Data["quick"])
+	case "muteChat":
+		match.setChatMute(playerId, payload.Data["playerId"], true)
+	case "unmuteChat":
+		match.setChatMute(playerId, payload.Data["playerId"], false)
 	default:
 		logging.Info("invalid payload type:", zap.String("type", payload.Type))
 	}
 }
+
+// Handler for when the simul host sends a message, routed by its matchId
+func (s *server) handleSimulMessage(
+	playerId string,
+	session *simulSession,
+	payload payload,
+) {
+	if payload.Type == "simulDashboard" {
+		session.sendDashboard()
+		return
+	}
+	matchId := payload.Data["matchId"]
+	match, exist := session.getMatch(matchId)
+	if !exist {
+		logging.Info("invalid simul board", zap.String("match_id", matchId))
+		return
+	}
+	s.handleWebSocketMessage(session.connFor(matchId), playerId, match, payload)
+}
+
+// checkPrimaryDevice method    rejects game data sent from a secondary device of the player
+func (s *server) checkPrimaryDevice(
+	conn connection,
+	match *Match,
+	playerId string,
+) bool {
+	player, exist := match.getPlayerWithId(playerId)
+	if !exist || player.isPrimary(conn) {
+		return true
+	}
+	player.writeJsonTo(conn, errorResponse{
+		Type:  "error",
+		Error: ErrStatusNotPrimaryDevice,
+	})
+	return false
+}
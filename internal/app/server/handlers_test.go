@@ -0,0 +1,63 @@
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// testConn struct    records what the server writes to a socket
+type testConn struct {
+	mu       sync.Mutex
+	messages []interface{}
+}
+
+func (c *testConn) WriteJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, v)
+	return nil
+}
+
+func (c *testConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	return nil
+}
+
+func (c *testConn) received(errStatus string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, msg := range c.messages {
+		if resp, ok := msg.(errorResponse); ok && resp.Error == errStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSecondaryDeviceCannotResign(t *testing.T) {
+	match := testMatch(t, false,
+		testPlayer("white", 1500, 1510, 1500, 1490),
+		testPlayer("black", 1500, 1510, 1500, 1490),
+	)
+	white, _ := match.getPlayerWithId("white")
+	primary, secondary := &testConn{}, &testConn{}
+	white.addConn(primary, 0)
+	white.addConn(secondary, 0)
+	go match.start()
+	defer match.end()
+
+	s := &server{}
+	s.handleWebSocketMessage(secondary, "white", match, payload{
+		Type:      "gameData",
+		Data:      map[string]string{"action": "resign"},
+		CreatedAt: time.Now(),
+	})
+	if !secondary.received(ErrStatusNotPrimaryDevice) {
+		t.Error("secondary device was not told it is not the primary device")
+	}
+	if match.isEnded() || match.game.outcome() != chess.NoOutcome {
+		t.Error("resignation from a secondary device ended the match")
+	}
+}
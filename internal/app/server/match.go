@@ -2,12 +2,13 @@ package server
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/chess-vn/slchess/internal/domains/dtos"
 	"github.com/chess-vn/slchess/internal/domains/entities"
 	"github.com/chess-vn/slchess/pkg/logging"
-	"github.com/chess-vn/slchess/pkg/utils"
 	"github.com/gorilla/websocket"
 	"github.com/notnil/chess"
 	"go.uber.org/zap"
@@ -20,27 +21,52 @@ const (
 
 type Match struct {
 	id      string
+	mode    TeamMode
 	players []*player
+	teams   []*team
 	game    *game
 	moveCh  chan move
 	timer   *time.Timer
 	startAt time.Time
 	cfg     MatchConfig
+	simul   *simulSession
 
-	endGameHandler   func(*Match)
-	saveGameHandler  func(*Match)
-	abortGameHandler func(*Match)
+	chat       chatLog
+	chatFilter ChatFilter
 
+	adjournOffer      *Side
+	adjournedAt       time.Time
+	firstMoveDeadline time.Time
+	noShowSide        *Side
+	// resumedAdjournment is set until every team is back on an adjourned match
+	resumedAdjournment bool
+
+	endGameHandler     func(*Match)
+	saveGameHandler    func(*Match)
+	abortGameHandler   func(*Match)
+	adjournGameHandler func(*Match)
+
+	// done is closed once the match ended, it stops the event loop and releases its senders
+	done  chan struct{}
 	ended bool
 	mu    sync.Mutex
 }
 
 type MatchConfig struct {
+	GameMode           string
 	MatchDuration      time.Duration
 	ClockIncrement     time.Duration
 	CancelTimeout      time.Duration
 	DisconnectTimeout  time.Duration
 	MaxLagForgivenTime time.Duration
+	FirstMoveTimeout   time.Duration
+	Untimed            bool
+	Casual             bool
+	Adjournable        bool
+	AdjournTimeout     time.Duration
+	ChatMaxLength      int
+	ChatRateLimit      int
+	ChatRateWindow     time.Duration
 }
 
 type matchResponse struct {
@@ -49,11 +75,22 @@ type matchResponse struct {
 }
 
 type gameStateResponse struct {
+	Version  int      `json:"version,omitempty"`
 	Outcome  string   `json:"outcome"`
 	Method   string   `json:"method"`
 	Fen      string   `json:"fen"`
 	Clocks   []string `json:"clocks"`
 	Statuses []string `json:"statuses,omitempty"`
+
+	// Since version 2
+	Ply         int                       `json:"ply"`
+	Turn        string                    `json:"turn,omitempty"`
+	Check       bool                      `json:"check"`
+	LastMove    *moveResponse             `json:"lastMove,omitempty"`
+	LegalMoves  []string                  `json:"legalMoves,omitempty"`
+	Captured    *capturedMaterialResponse `json:"captured,omitempty"`
+	Repetitions int                       `json:"repetitions,omitempty"`
+	Moves       []moveResponse            `json:"moves,omitempty"`
 }
 
 type playerStatusResponse struct {
@@ -74,7 +111,11 @@ type drawOfferResponse struct {
 }
 
 func (match *Match) start() {
-	for move := range match.moveCh {
+	for {
+		move, ok := match.nextMove()
+		if !ok {
+			return
+		}
 		player, exist := match.getPlayerWithId(move.playerId)
 		if !exist {
 			player.writeJson(errorResponse{
@@ -85,11 +126,12 @@ func (match *Match) start() {
 		}
 		switch move.control {
 		case ABORT:
-			if match.currentPly() > 1 {
+			if !match.canAbort(player) {
 				player.writeJson(errorResponse{
 					Type:  "error",
 					Error: ErrStatusAbortInvalidPly,
 				})
+				continue
 			}
 			match.abort()
 			continue
@@ -107,6 +149,41 @@ func (match *Match) start() {
 				match.sendDrawOfferNotification(player, DECLINED)
 			}
 			continue
+		case SELECT_PIECE:
+			if errStatus := match.selectPiece(player, move.piece); errStatus != "" {
+				player.writeJson(errorResponse{
+					Type:  "error",
+					Error: errStatus,
+				})
+			}
+			continue
+		case OFFER_ADJOURN:
+			if errStatus := match.offerAdjourn(player); errStatus != "" {
+				player.writeJson(errorResponse{
+					Type:  "error",
+					Error: errStatus,
+				})
+			}
+			continue
+		case ACCEPT_ADJOURN:
+			if errStatus := match.acceptAdjourn(player); errStatus != "" {
+				player.writeJson(errorResponse{
+					Type:  "error",
+					Error: errStatus,
+				})
+			}
+			continue
+		case DECLINE_ADJOURN:
+			match.declineAdjourn(player)
+			continue
+		case FLAG:
+			// Sent by the simul host clock, which is not driven by the board's moves
+			match.game.outOfTime(player.Side)
+			logging.Info("out of time", zap.String("player_id", player.Id))
+			match.notifyPlayers(match.gameState(false))
+			match.save()
+			match.end()
+			continue
 		default:
 			if expectedId := match.getCurrentTurnPlayer().Id; player.Id != expectedId {
 				player.writeJson(errorResponse{
@@ -120,6 +197,31 @@ func (match *Match) start() {
 				})
 				continue
 			}
+			// Clients may send SAN or long algebraic, moves are stored as UCI
+			uci, moveErr := match.game.normalizeMove(move.uci, Notation(move.notation))
+			if moveErr != nil {
+				player.writeJson(moveErrorResponse{
+					Type:       "error",
+					Error:      moveErr.Status,
+					Reason:     moveErr.Reason,
+					Input:      move.uci,
+					Notation:   move.notation,
+					Candidates: moveErr.Candidates,
+				})
+				continue
+			}
+			move.uci = uci
+			movingTeam := match.getCurrentTurnTeam()
+			if match.mode == HAND_AND_BRAIN {
+				if errStatus := match.checkPieceSelection(movingTeam, move.uci); errStatus != "" {
+					player.writeJson(errorResponse{
+						Type:  "error",
+						Error: errStatus,
+					})
+					continue
+				}
+			}
+			firstMove := match.usesFirstMoveCountdown()
 			err := match.game.move(move)
 			if err != nil {
 				player.writeJson(errorResponse{
@@ -128,42 +230,43 @@ func (match *Match) start() {
 				})
 				continue
 			}
+			movingTeam.pieceSelection = chess.NoPieceType
+			match.adjournOffer = nil
 
 			// If making move, update clock
-			timeTaken := time.Since(player.TurnStartedAt)
-			lagForgiven := match.calculateLagForgiven(move.createdAt)
-			player.updateClock(timeTaken, lagForgiven, match.cfg.ClockIncrement)
+			var timeTaken, lagForgiven, increment time.Duration
+			if !movingTeam.TurnStartedAt.IsZero() {
+				timeTaken = time.Since(movingTeam.TurnStartedAt)
+			}
+			if !match.cfg.Untimed && !match.usesSharedClock(movingTeam) && !firstMove {
+				lagForgiven = match.calculateLagForgiven(move.createdAt)
+				increment = match.cfg.ClockIncrement
+				movingTeam.updateClock(timeTaken, lagForgiven, increment)
+			}
+			match.game.recordClock(match.teamClock(movingTeam))
+			match.game.recordTiming(timeTaken, lagForgiven, increment)
 
 			// If clock runs out, end the game
-			if player.Clock <= 0 {
-				match.game.outOfTime(player.Side)
+			if movingTeam.Clock <= 0 {
+				match.game.outOfTime(movingTeam.Side)
 				logging.Info("out of time", zap.String("player_id", player.Id))
 			} else {
 				// else next turn
-				currentTurnPlayer := match.getCurrentTurnPlayer()
-				currentTurnPlayer.TurnStartedAt = time.Now()
-				match.setTimer(currentTurnPlayer.Clock)
+				match.startTurnTimer()
 				logging.Info(
 					"new turn",
-					zap.String("player_id", currentTurnPlayer.Id),
-					zap.String("clock_w", match.players[0].Clock.String()),
-					zap.String("clock_b", match.players[1].Clock.String()),
+					zap.String("player_id", match.getCurrentTurnPlayer().Id),
+					zap.String("clock_w", match.teamClock(match.teams[0]).String()),
+					zap.String("clock_b", match.teamClock(match.teams[1]).String()),
 				)
 			}
 		}
 
-		match.notifyPlayers(gameStateResponse{
-			Outcome: match.game.outcome().String(),
-			Method:  match.game.method(),
-			Fen:     match.game.FEN(),
-			Clocks: []string{
-				match.players[0].Clock.String(),
-				match.players[1].Clock.String(),
-			},
-		})
+		match.notifyPlayers(match.gameState(false))
 
 		// Save game state
 		match.save()
+		match.updateSimul(match.game.outcome() != chess.NoOutcome)
 
 		// Aborted because both player had disconnected
 		if match.isEnded() {
@@ -249,32 +352,11 @@ func (m *Match) notifyAboutDeclinedOffer(resp playerStatusResponse) {
 
 func (m *Match) syncPlayer(player *player) {
 	resp := matchResponse{
-		Type: "gameState",
-		GameState: gameStateResponse{
-			Outcome: m.game.outcome().String(),
-			Method:  m.game.method(),
-			Fen:     m.game.FEN(),
-			Clocks:  make([]string, len(m.players)),
-			Statuses: []string{
-				m.players[0].Status.String(),
-				m.players[1].Status.String(),
-			},
-		},
-	}
-	currentTurnPlayer := m.getCurrentTurnPlayer()
-	timePassed := time.Since(currentTurnPlayer.TurnStartedAt)
-	for i, player := range m.players {
-		if player.Id == currentTurnPlayer.Id {
-			clock := player.Clock - timePassed
-			if clock > 0 {
-				resp.GameState.Clocks[i] = clock.String()
-			} else {
-				resp.GameState.Clocks[i] = (0 * time.Second).String()
-			}
-		} else {
-			resp.GameState.Clocks[i] = player.Clock.String()
-		}
+		Type:      "gameState",
+		GameState: m.gameState(true),
 	}
+	resp.GameState.Clocks = m.liveClocks()
+	resp.GameState.Statuses = m.statuses()
 	err := player.writeJson(resp)
 	if err != nil {
 		logging.Error(
@@ -282,6 +364,39 @@ func (m *Match) syncPlayer(player *player) {
 			zap.String("player_id", player.Id),
 		)
 	}
+	m.sendFirstMoveCountdown(player)
+}
+
+// liveClocks method    returns the team clocks with the running turn already deducted
+func (m *Match) liveClocks() []string {
+	clocks := []string{}
+	currentTurnTeam := m.getCurrentTurnTeam()
+	timePassed := time.Since(currentTurnTeam.TurnStartedAt)
+	for _, team := range m.teams {
+		if m.cfg.Untimed {
+			break
+		}
+		clock := m.teamClock(team)
+		if team.Side == currentTurnTeam.Side &&
+			!m.usesSharedClock(team) &&
+			!m.usesFirstMoveCountdown() &&
+			!currentTurnTeam.TurnStartedAt.IsZero() {
+			clock -= timePassed
+		}
+		if clock < 0 {
+			clock = 0
+		}
+		clocks = append(clocks, clock.String())
+	}
+	return clocks
+}
+
+func (m *Match) statuses() []string {
+	statuses := make([]string, 0, len(m.players))
+	for _, player := range m.players {
+		statuses = append(statuses, player.status().String())
+	}
+	return statuses
 }
 
 func (m *Match) syncPlayerWithId(id string) {
@@ -305,37 +420,156 @@ func (m *Match) getPlayerWithId(id string) (*player, bool) {
 	return nil, false
 }
 
-func (m *Match) getCurrentTurnPlayer() *player {
+func (m *Match) getTeamOf(player *player) *team {
+	if player.Side == WHITE_SIDE {
+		return m.teams[0]
+	}
+	return m.teams[1]
+}
+
+func (m *Match) getCurrentTurnTeam() *team {
 	if m.game.Position().Turn() == chess.White {
-		return m.players[0]
+		return m.teams[0]
 	}
-	return m.players[1]
+	return m.teams[1]
 }
 
-func (m *Match) getNextTurnPlayer() *player {
+func (m *Match) getNextTurnTeam() *team {
 	if m.game.Position().Turn() == chess.White {
-		return m.players[1]
+		return m.teams[1]
+	}
+	return m.teams[0]
+}
+
+// getCurrentTurnPlayer method    returns the team member allowed to make the next move
+func (m *Match) getCurrentTurnPlayer() *player {
+	team := m.getCurrentTurnTeam()
+	if m.mode == HAND_AND_BRAIN {
+		for _, player := range team.players {
+			if player.Role == HAND {
+				return player
+			}
+		}
+	}
+	return team.players[0]
+}
+
+func (m *Match) clocks() []string {
+	clocks := make([]string, 0, len(m.teams))
+	if m.cfg.Untimed {
+		return clocks
+	}
+	for _, team := range m.teams {
+		clocks = append(clocks, m.teamClock(team).String())
+	}
+	return clocks
+}
+
+// teamClock method    returns the team clock, or the shared host clock in a simul
+func (m *Match) teamClock(team *team) time.Duration {
+	if m.usesSharedClock(team) {
+		return m.simul.hostClock()
+	}
+	return team.Clock
+}
+
+// turnTimeout method    returns how long the team to move may think before the match ends
+func (m *Match) turnTimeout(team *team) time.Duration {
+	if m.usesFirstMoveCountdown() && !m.firstMoveDeadline.IsZero() {
+		return time.Until(m.firstMoveDeadline)
+	}
+	if m.cfg.Untimed {
+		return m.cfg.MatchDuration
+	}
+	if m.usesSharedClock(team) {
+		// The simul session flags the host itself, this is only a safety net
+		return m.simul.hostClock() + time.Second
 	}
-	return m.players[0]
+	return team.Clock
+}
+
+func (m *Match) usesSharedClock(team *team) bool {
+	return m.simul != nil && m.simul.clock != nil && team.hasPlayer(m.simul.hostId)
+}
+
+// updateSimul method    reports the board state to the simul session, if any
+func (m *Match) updateSimul(ended bool) {
+	if m.simul == nil {
+		return
+	}
+	waiting := !ended && m.getCurrentTurnTeam().hasPlayer(m.simul.hostId)
+	m.simul.updateBoard(m.id, waiting, ended)
 }
 
 func (m *Match) currentPly() int {
 	return len(m.game.moves)
 }
 
-func (m *Match) processMove(playerId, moveUci string, createdAt time.Time) {
-	m.moveCh <- move{
-		playerId:  playerId,
-		uci:       moveUci,
-		control:   NONE,
-		createdAt: createdAt,
+// plyRecords method    lists the clock and timing data of every ply played so far
+func (m *Match) plyRecords() []dtos.PlyRecordRequest {
+	plies := make([]dtos.PlyRecordRequest, 0, len(m.game.moves))
+	for i, move := range m.game.moves {
+		plies = append(plies, dtos.PlyRecordRequest{
+			Ply:         i + 1,
+			PlayerId:    move.playerId,
+			Uci:         move.uci,
+			Clock:       move.clock.String(),
+			TimeTaken:   move.timeTaken.String(),
+			LagForgiven: move.lagForgiven.String(),
+			Increment:   move.increment.String(),
+			ReceivedAt:  move.receivedAt,
+		})
 	}
+	return plies
+}
+
+func (m *Match) processMove(playerId, moveStr, notation string, createdAt time.Time) {
+	m.send(move{
+		playerId:   playerId,
+		uci:        moveStr,
+		notation:   strings.ToLower(notation),
+		control:    NONE,
+		createdAt:  createdAt,
+		receivedAt: time.Now(),
+	})
+}
+
+func (m *Match) processPieceSelection(playerId, piece string) {
+	m.send(move{
+		playerId: playerId,
+		piece:    piece,
+		control:  SELECT_PIECE,
+	})
 }
 
 func (m *Match) processGameControl(playerId string, control GameControl) {
-	m.moveCh <- move{
+	m.send(move{
 		playerId: playerId,
 		control:  control,
+	})
+}
+
+// send method    hands the move to the event loop, it is dropped once the match ended
+func (m *Match) send(move move) {
+	select {
+	case m.moveCh <- move:
+	case <-m.done:
+	}
+}
+
+// nextMove method    waits for the next move, it reports false once the match ended
+func (m *Match) nextMove() (move, bool) {
+	select {
+	case move := <-m.moveCh:
+		// The match may have ended while the move was handed over
+		select {
+		case <-m.done:
+			return move, false
+		default:
+			return move, true
+		}
+	case <-m.done:
+		return move{}, false
 	}
 }
 
@@ -346,11 +580,10 @@ func (m *Match) abort() {
 		return
 	}
 	m.ended = true
-	if !utils.IsClosed(m.moveCh) {
-		close(m.moveCh)
-	}
+	close(m.done)
 	// Fire off the timer to remove end game handling job
 	m.skipTimer()
+	m.updateSimul(true)
 	for _, player := range m.players {
 		player.writeControl(
 			websocket.CloseMessage,
@@ -375,12 +608,17 @@ func (m *Match) end() {
 		return
 	}
 	m.ended = true
-	if !utils.IsClosed(m.moveCh) {
-		close(m.moveCh)
-	}
+	close(m.done)
 	// Fire off the timer to remove end game handling job
 	m.skipTimer()
+	if m.resumedAdjournment {
+		// Not every team came back, keep the match adjourned
+		m.disconnectPlayers("match adjourned", time.Now().Add(5*time.Second))
+		m.adjournGameHandler(m)
+		return
+	}
 	m.checkTimeout()
+	m.updateSimul(true)
 	m.disconnectPlayers("match ended", time.Now().Add(5*time.Second))
 	m.endGameHandler(m)
 }
@@ -405,7 +643,7 @@ func (m *Match) setTimer(d time.Duration) {
 	m.timer = time.NewTimer(d)
 	go func() {
 		<-m.timer.C
-		m.end()
+		m.handleTimeout()
 	}()
 	logging.Info(
 		"clock set",
@@ -430,33 +668,149 @@ func configForGameMode(gameMode string) (MatchConfig, error) {
 		return MatchConfig{}, err
 	}
 	return MatchConfig{
+		GameMode:          gameMode,
 		MatchDuration:     gm.Time,
 		ClockIncrement:    gm.Increment,
 		CancelTimeout:     30 * time.Second,
 		DisconnectTimeout: 120 * time.Second,
+		FirstMoveTimeout:  30 * time.Second,
+		Adjournable:       gm.Time >= minAdjournableDuration,
+		AdjournTimeout:    7 * 24 * time.Hour,
+		ChatMaxLength:     280,
+		ChatRateLimit:     5,
+		ChatRateWindow:    10 * time.Second,
 	}, nil
 }
 
-func (m *Match) getNewPlayerRatings() ([]float64, []float64, error) {
+/*
+getNewPlayerRatings method    returns new ratings, RDs and volatilities ordered as match players.
+Team members were precomputed from one team level update, so they share the same changes.
+*/
+func (m *Match) getNewPlayerRatings() ([]float64, []float64, []float64, error) {
+	// Index of the precomputed rating: 0 - win, 1 - draw, 2 - loss
+	var whiteIdx, blackIdx int
 	switch m.game.outcome() {
 	case chess.WhiteWon:
-		return []float64{m.players[0].NewRatings[0], m.players[1].NewRatings[2]},
-			[]float64{m.players[0].NewRDs[0], m.players[1].NewRDs[2]},
-			nil
+		whiteIdx, blackIdx = 0, 2
 	case chess.BlackWon:
-		return []float64{m.players[0].NewRatings[2], m.players[1].NewRatings[0]},
-			[]float64{m.players[0].NewRDs[2], m.players[1].NewRDs[0]},
-			nil
+		whiteIdx, blackIdx = 2, 0
 	case chess.Draw:
-		return []float64{m.players[0].NewRatings[1], m.players[1].NewRatings[1]},
-			[]float64{m.players[0].NewRDs[1], m.players[1].NewRDs[1]},
-			nil
+		whiteIdx, blackIdx = 1, 1
 	case chess.NoOutcome:
-		return []float64{m.players[0].Rating, m.players[1].Rating},
-			[]float64{m.players[0].RD, m.players[1].RD},
-			nil
+		newRatings := make([]float64, 0, len(m.players))
+		newRDs := make([]float64, 0, len(m.players))
+		newVolatilities := make([]float64, 0, len(m.players))
+		for _, player := range m.players {
+			newRatings = append(newRatings, player.Rating)
+			newRDs = append(newRDs, player.RD)
+			newVolatilities = append(newVolatilities, player.Volatility)
+		}
+		return newRatings, newRDs, newVolatilities, nil
+	default:
+		return nil, nil, nil, ErrInvalidOutcome
+	}
+
+	newRatings := make([]float64, 0, len(m.players))
+	newRDs := make([]float64, 0, len(m.players))
+	newVolatilities := make([]float64, 0, len(m.players))
+	for _, player := range m.players {
+		idx := whiteIdx
+		if player.Side == BLACK_SIDE {
+			idx = blackIdx
+		}
+		if len(player.NewRatings) <= idx || len(player.NewRDs) <= idx {
+			return nil, nil, nil, ErrInvalidOutcome
+		}
+		newRatings = append(newRatings, player.NewRatings[idx])
+		newRDs = append(newRDs, player.NewRDs[idx])
+		// Unrated players come without precomputed volatilities
+		volatility := player.Volatility
+		if len(player.NewVolatilities) > idx {
+			volatility = player.NewVolatilities[idx]
+		}
+		newVolatilities = append(newVolatilities, volatility)
+	}
+	return newRatings, newRDs, newVolatilities, nil
+}
+
+// recordRequest method    returns the end game request recording the match and its rating changes
+func (m *Match) recordRequest(endedAt time.Time) (dtos.MatchRecordRequest, error) {
+	newRatings, newRDs, newVolatilities, err := m.getNewPlayerRatings()
+	if err != nil {
+		return dtos.MatchRecordRequest{}, err
+	}
+	matchRecordReq := dtos.MatchRecordRequest{
+		MatchId:   m.id,
+		GameMode:  m.cfg.GameMode,
+		Players:   make([]dtos.PlayerRecordRequest, 0, len(m.players)),
+		Pgn:       m.pgn(),
+		Plies:     m.plyRecords(),
+		Chat:      m.chat.records(),
+		StartedAt: m.startAt,
+		EndedAt:   endedAt,
+		Results:   m.getResults(),
+		Casual:    !m.rated(),
+	}
+	for i, player := range m.players {
+		team := 0
+		if player.Side == BLACK_SIDE {
+			team = 1
+		}
+		matchRecordReq.Players = append(
+			matchRecordReq.Players,
+			dtos.PlayerRecordRequest{
+				Id:            player.Id,
+				OldRating:     player.Rating,
+				NewRating:     newRatings[i],
+				OldRD:         player.RD,
+				NewRD:         newRDs[i],
+				Team:          team,
+				NewVolatility: newVolatilities[i],
+				Unrated:       !m.ratesPlayer(player),
+			},
+		)
+	}
+	return matchRecordReq, nil
+}
+
+// rated method    reports whether the match changes the ratings of its players, simul games never do
+func (m *Match) rated() bool {
+	return !m.cfg.Casual && m.simul == nil
+}
+
+/*
+ratesPlayer method    reports whether the result changes the rating of the player, a match without an outcome never does.
+Casual games still move the provisional rating of guests, simul games move no rating.
+*/
+func (m *Match) ratesPlayer(player *player) bool {
+	if m.game.outcome() == chess.NoOutcome {
+		return false
+	}
+	return m.rated() || (player.Provisional && m.simul == nil)
+}
+
+// getResults method    returns the score of each match player
+func (m *Match) getResults() []float64 {
+	results := make([]float64, 0, len(m.players))
+	for _, player := range m.players {
+		switch m.game.outcome() {
+		case chess.WhiteWon:
+			if player.Side == WHITE_SIDE {
+				results = append(results, 1.0)
+			} else {
+				results = append(results, 0.0)
+			}
+		case chess.BlackWon:
+			if player.Side == BLACK_SIDE {
+				results = append(results, 1.0)
+			} else {
+				results = append(results, 0.0)
+			}
+		default:
+			results = append(results, 0.5)
+		}
 	}
-	return nil, nil, ErrInvalidOutcome
+	return results
 }
 
 func (m *Match) calculateLagForgiven(moveCreatedAt time.Time) time.Duration {
@@ -468,33 +822,27 @@ func (m *Match) calculateLagForgiven(moveCreatedAt time.Time) time.Duration {
 }
 
 func (m *Match) checkTimeout() {
-	if m.players[0].Status == CONNECTED &&
-		m.players[1].Status == CONNECTED {
+	whiteStatus := m.teams[0].status()
+	blackStatus := m.teams[1].status()
+	if whiteStatus == CONNECTED &&
+		blackStatus == CONNECTED {
 		return
 	}
-	if m.players[0].Status == INIT ||
-		m.players[1].Status == INIT {
+	if whiteStatus == INIT ||
+		blackStatus == INIT {
 		m.disconnectPlayers("match cancelled", time.Now().Add(5*time.Second))
 	}
-	if m.players[0].Status == DISCONNECTED &&
-		m.players[1].Status == CONNECTED {
-		m.game.disconnectTimeout(m.players[0].Side)
-	} else if m.players[0].Status == CONNECTED &&
-		m.players[1].Status == DISCONNECTED {
-		m.game.disconnectTimeout(m.players[1].Side)
-	} else if m.players[0].Status == DISCONNECTED &&
-		m.players[1].Status == DISCONNECTED {
+	if whiteStatus == DISCONNECTED &&
+		blackStatus == CONNECTED {
+		m.game.disconnectTimeout(m.teams[0].Side)
+	} else if whiteStatus == CONNECTED &&
+		blackStatus == DISCONNECTED {
+		m.game.disconnectTimeout(m.teams[1].Side)
+	} else if whiteStatus == DISCONNECTED &&
+		blackStatus == DISCONNECTED {
 		m.game.drawByTimeout()
 	}
-	m.notifyPlayers(gameStateResponse{
-		Outcome: m.game.outcome().String(),
-		Method:  m.game.method(),
-		Fen:     m.game.FEN(),
-		Clocks: []string{
-			m.players[0].Clock.String(),
-			m.players[1].Clock.String(),
-		},
-	})
+	m.notifyPlayers(m.gameState(false))
 }
 
 func (m *Match) disconnectPlayers(msg string, deadline time.Time) {
@@ -509,3 +857,95 @@ func (m *Match) disconnectPlayers(msg string, deadline time.Time) {
 		)
 	}
 }
+
+// sendTeamMessage method    forwards a message to the sender's teammates only
+func (m *Match) sendTeamMessage(sender *player, content string) {
+	for _, player := range m.getTeamOf(sender).players {
+		if player.Id == sender.Id {
+			continue
+		}
+		err := player.writeJson(teamMessageResponse{
+			Type:      "teamMessage",
+			PlayerId:  sender.Id,
+			Content:   content,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		})
+		if err != nil {
+			logging.Error(
+				"couldn't send team message to player: ",
+				zap.String("player_id", player.Id),
+			)
+		}
+	}
+}
+
+func (m *Match) sendTeamMessageWithId(senderId, content string) {
+	sender, exist := m.getPlayerWithId(senderId)
+	if !exist {
+		logging.Error(
+			"couldn't send team message: ",
+			zap.String("player_id", senderId),
+		)
+		return
+	}
+	m.sendTeamMessage(sender, content)
+}
+
+/*
+selectPiece method    records the piece type named by the brain of the team to move.
+An empty string is returned on success, an error status otherwise.
+*/
+func (m *Match) selectPiece(player *player, piece string) string {
+	if m.mode != HAND_AND_BRAIN || player.Role != BRAIN {
+		return ErrStatusWrongRole
+	}
+	team := m.getCurrentTurnTeam()
+	if !team.hasPlayer(player.Id) {
+		return ErrStatusWrongTurn
+	}
+	pieceType := parsePieceType(piece)
+	if pieceType == chess.NoPieceType {
+		return ErrStatusInvalidPiece
+	}
+	movable := false
+	board := m.game.Position().Board()
+	for _, mv := range m.game.ValidMoves() {
+		if board.Piece(mv.S1()).Type() == pieceType {
+			movable = true
+			break
+		}
+	}
+	if !movable {
+		return ErrStatusInvalidPiece
+	}
+	team.pieceSelection = pieceType
+	for _, p := range m.players {
+		err := p.writeJson(pieceSelectionResponse{
+			Type:     "pieceSelection",
+			PlayerId: player.Id,
+			Piece:    pieceType.String(),
+		})
+		if err != nil {
+			logging.Error(
+				"couldn't send piece selection to player: ",
+				zap.String("player_id", p.Id),
+			)
+		}
+	}
+	return ""
+}
+
+// checkPieceSelection method    checks that the hand moves the piece type named by the brain
+func (m *Match) checkPieceSelection(team *team, uci string) string {
+	if team.pieceSelection == chess.NoPieceType {
+		return ErrStatusPieceNotSelected
+	}
+	pieceType, err := m.game.pieceTypeOf(uci)
+	if err != nil {
+		return ErrStatusInvalidMove
+	}
+	if pieceType != team.pieceSelection {
+		return ErrStatusWrongPiece
+	}
+	return ""
+}
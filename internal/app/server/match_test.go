@@ -0,0 +1,116 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/aws/auth"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/notnil/chess"
+)
+
+// testMatch function    builds a match between two players without starting its event loop
+func testMatch(t *testing.T, casual bool, player1, player2 entities.Player) *Match {
+	t.Helper()
+	config, err := configForGameMode("10+0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Casual = casual
+	players := playersOf(entities.ActiveMatch{Player1: player1, Player2: player2})
+	return &Match{
+		id:      "match",
+		game:    newGame(),
+		players: players,
+		teams:   groupTeams(players, []time.Duration{config.MatchDuration, config.MatchDuration}),
+		moveCh:  make(chan move),
+		done:    make(chan struct{}),
+		cfg:     config,
+
+		endGameHandler:  func(*Match) {},
+		saveGameHandler: func(*Match) {},
+	}
+}
+
+func testPlayer(id string, rating float64, newRatings ...float64) entities.Player {
+	return entities.Player{
+		Id:         id,
+		Rating:     rating,
+		RD:         100,
+		NewRatings: newRatings,
+		NewRDs:     []float64{90, 90, 90},
+	}
+}
+
+func TestRecordRequestWithoutOutcomeIsUnrated(t *testing.T) {
+	match := testMatch(t, false,
+		testPlayer("white", 1500, 1510, 1500, 1490),
+		testPlayer("black", 1500, 1510, 1500, 1490),
+	)
+	req, err := match.recordRequest(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, player := range req.Players {
+		if !player.Unrated {
+			t.Errorf("player %s of a match without outcome is rated", player.Id)
+		}
+	}
+}
+
+func TestRecordRequestOfFinishedRatedMatchIsRated(t *testing.T) {
+	match := testMatch(t, false,
+		testPlayer("white", 1500, 1510, 1500, 1490),
+		testPlayer("black", 1500, 1510, 1500, 1490),
+	)
+	match.game.Resign(chess.Black)
+	req, err := match.recordRequest(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, player := range req.Players {
+		if player.Unrated {
+			t.Errorf("player %s of a finished rated match is unrated", player.Id)
+		}
+	}
+	if req.Players[0].NewRating != 1510 || req.Players[1].NewRating != 1490 {
+		t.Errorf("new ratings = %v/%v, want 1510/1490", req.Players[0].NewRating, req.Players[1].NewRating)
+	}
+}
+
+func TestGuestInCasualMatchIsRated(t *testing.T) {
+	guest := testPlayer("guest", 1500, 1510, 1500, 1490)
+	guest.Provisional = true
+	activeMatch := entities.ActiveMatch{
+		MatchId:  "match",
+		GameMode: "10+0",
+		Casual:   true,
+		Player1:  guest,
+		Player2:  testPlayer("user", 1500, 1500, 1500, 1500),
+	}
+	// The guest flag has to survive the match ticket the server is handed
+	ticket, err := auth.SignMatchTicket(activeMatch, false, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := auth.ParseMatchTicket(ticket, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	activeMatch = claims.ActiveMatch()
+	match := testMatch(t, activeMatch.Casual, activeMatch.Player1, activeMatch.Player2)
+	match.game.Resign(chess.Black)
+	req, err := match.recordRequest(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !req.Casual {
+		t.Error("casual match recorded as rated")
+	}
+	if req.Players[0].Unrated || req.Players[0].NewRating != 1510 {
+		t.Errorf("guest unrated = %v with new rating %v, want rated at 1510", req.Players[0].Unrated, req.Players[0].NewRating)
+	}
+	if !req.Players[1].Unrated {
+		t.Error("registered player of a casual match is rated")
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/notnil/chess"
+)
+
+type Notation string
+
+const (
+	UCI Notation = "uci"
+	SAN Notation = "san"
+	LAN Notation = "lan"
+)
+
+var (
+	uciPattern = regexp.MustCompile(`^([a-h][1-8])([a-h][1-8])([qrbn])?$`)
+	lanPattern = regexp.MustCompile(`^([KQRBN])?([a-h][1-8])[-x]([a-h][1-8])(?:=?([QRBNqrbn]))?$|^([KQRBN])([a-h][1-8])([a-h][1-8])(?:=?([QRBNqrbn]))?$`)
+	sanPattern = regexp.MustCompile(`^([KQRBN])?([a-h])?([1-8])?x?([a-h][1-8])(?:=?([QRBNqrbn]))?$`)
+)
+
+// moveError explains why a move input could not be turned into a legal move
+type moveError struct {
+	Status     string
+	Reason     string
+	Candidates []string
+}
+
+type moveErrorResponse struct {
+	Type       string   `json:"type"`
+	Error      string   `json:"error"`
+	Reason     string   `json:"reason"`
+	Input      string   `json:"input"`
+	Notation   string   `json:"notation,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// moveQuery describes the legal moves an input may refer to
+type moveQuery struct {
+	piece    chess.PieceType
+	fromFile string
+	fromRank string
+	from     string
+	to       string
+	promo    chess.PieceType
+	castle   chess.MoveTag
+}
+
+/*
+normalizeMove method    converts a move written in UCI, long algebraic or SAN to UCI.
+When no notation is given it is detected from the input.
+*/
+func (g *game) normalizeMove(input string, notation Notation) (string, *moveError) {
+	input = cleanMoveInput(input)
+	if input == "" {
+		return "", &moveError{
+			Status: ErrStatusInvalidMove,
+			Reason: "empty move",
+		}
+	}
+	if notation == "" {
+		notation = detectNotation(input)
+	}
+
+	var (
+		query moveQuery
+		ok    bool
+	)
+	switch notation {
+	case UCI:
+		query, ok = parseUci(input)
+	case LAN:
+		query, ok = parseLan(input)
+	case SAN:
+		query, ok = parseSan(input)
+	default:
+		return "", &moveError{
+			Status: ErrStatusUnknownNotation,
+			Reason: fmt.Sprintf("unknown notation %q, expected uci, lan or san", notation),
+		}
+	}
+	if !ok {
+		return "", &moveError{
+			Status: ErrStatusInvalidMove,
+			Reason: fmt.Sprintf("%q is not a valid %s move", input, notation),
+		}
+	}
+
+	pos := g.Position()
+	matches := []*chess.Move{}
+	for _, move := range g.ValidMoves() {
+		if query.matches(pos, move) {
+			matches = append(matches, move)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", &moveError{
+			Status: ErrStatusInvalidMove,
+			Reason: fmt.Sprintf("%q is not a legal move in this position", input),
+		}
+	case 1:
+		return chess.UCINotation{}.Encode(pos, matches[0]), nil
+	default:
+		candidates := make([]string, 0, len(matches))
+		for _, move := range matches {
+			candidates = append(candidates, chess.AlgebraicNotation{}.Encode(pos, move))
+		}
+		return "", &moveError{
+			Status:     ErrStatusAmbiguousMove,
+			Reason:     fmt.Sprintf("%q matches %d legal moves", input, len(matches)),
+			Candidates: candidates,
+		}
+	}
+}
+
+func (q moveQuery) matches(pos *chess.Position, move *chess.Move) bool {
+	if q.castle != 0 {
+		return move.HasTag(q.castle)
+	}
+	if move.S2().String() != q.to || move.Promo() != q.promo {
+		return false
+	}
+	if q.from != "" && move.S1().String() != q.from {
+		return false
+	}
+	if q.fromFile != "" && move.S1().File().String() != q.fromFile {
+		return false
+	}
+	if q.fromRank != "" && move.S1().Rank().String() != q.fromRank {
+		return false
+	}
+	// UCI does not name the piece
+	if q.piece == chess.NoPieceType {
+		return true
+	}
+	return pos.Board().Piece(move.S1()).Type() == q.piece
+}
+
+// cleanMoveInput function    drops annotations that do not change the move
+func cleanMoveInput(input string) string {
+	input = strings.TrimSpace(input)
+	input = strings.TrimSuffix(input, "e.p.")
+	input = strings.TrimRight(input, "+#!? ")
+	return strings.ReplaceAll(input, "0", "O")
+}
+
+func detectNotation(input string) Notation {
+	switch {
+	case uciPattern.MatchString(input):
+		return UCI
+	case lanPattern.MatchString(input):
+		return LAN
+	default:
+		return SAN
+	}
+}
+
+func parseCastle(input string) (moveQuery, bool) {
+	switch input {
+	case "O-O":
+		return moveQuery{castle: chess.KingSideCastle}, true
+	case "O-O-O":
+		return moveQuery{castle: chess.QueenSideCastle}, true
+	}
+	return moveQuery{}, false
+}
+
+func parseUci(input string) (moveQuery, bool) {
+	m := uciPattern.FindStringSubmatch(input)
+	if m == nil {
+		return moveQuery{}, false
+	}
+	return moveQuery{
+		from:  m[1],
+		to:    m[2],
+		promo: parsePromotion(m[3]),
+	}, true
+}
+
+func parseLan(input string) (moveQuery, bool) {
+	if query, ok := parseCastle(input); ok {
+		return query, true
+	}
+	m := lanPattern.FindStringSubmatch(input)
+	if m == nil {
+		return moveQuery{}, false
+	}
+	// The second alternative of the pattern has no separator
+	if m[2] == "" {
+		m = []string{m[0], m[5], m[6], m[7], m[8]}
+	}
+	return moveQuery{
+		piece: pieceOrPawn(m[1]),
+		from:  m[2],
+		to:    m[3],
+		promo: parsePromotion(m[4]),
+	}, true
+}
+
+func parseSan(input string) (moveQuery, bool) {
+	if query, ok := parseCastle(input); ok {
+		return query, true
+	}
+	m := sanPattern.FindStringSubmatch(input)
+	if m == nil {
+		return moveQuery{}, false
+	}
+	return moveQuery{
+		piece:    pieceOrPawn(m[1]),
+		fromFile: m[2],
+		fromRank: m[3],
+		to:       m[4],
+		promo:    parsePromotion(m[5]),
+	}, true
+}
+
+func pieceOrPawn(letter string) chess.PieceType {
+	if letter == "" {
+		return chess.Pawn
+	}
+	return parsePieceType(letter)
+}
+
+func parsePromotion(letter string) chess.PieceType {
+	if letter == "" {
+		return chess.NoPieceType
+	}
+	return parsePieceType(letter)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/chess-vn/slchess/pkg/pgn"
+	"github.com/notnil/chess"
+)
+
+// pgn method    returns the complete PGN of the match
+func (m *Match) pgn() string {
+	white, black := m.teams[0], m.teams[1]
+	date := m.startAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var clocks []time.Duration
+	if !m.cfg.Untimed {
+		clocks = make([]time.Duration, 0, len(m.game.moves))
+		for _, move := range m.game.moves {
+			clocks = append(clocks, move.clock)
+		}
+	}
+	return pgn.Write(pgn.Game{
+		Event:       m.event(),
+		Date:        date,
+		White:       teamName(white),
+		Black:       teamName(black),
+		WhiteElo:    white.averageRating(),
+		BlackElo:    black.averageRating(),
+		TimeControl: m.timeControl(),
+		Termination: m.termination(),
+		Outcome:     m.game.outcome(),
+		Positions:   m.game.Positions(),
+		Moves:       m.game.Moves(),
+		Clocks:      clocks,
+	})
+}
+
+func (m *Match) event() string {
+	switch {
+	case m.simul != nil:
+		return "Casual simul game"
+	case m.cfg.Casual:
+		return "Casual game"
+	case m.mode == HAND_AND_BRAIN:
+		return "Rated hand and brain game"
+	default:
+		return "Rated game"
+	}
+}
+
+// timeControl method    returns the PGN time control, base and increment in seconds
+func (m *Match) timeControl() string {
+	if m.cfg.Untimed {
+		return "-"
+	}
+	return pgn.TimeControl(m.cfg.MatchDuration, m.cfg.ClockIncrement)
+}
+
+// termination method    maps the game method, custom outcomes included, to the PGN Termination tag
+func (m *Match) termination() string {
+	switch m.game.customOutcome {
+	case WHITE_OUT_OF_TIME, BLACK_OUT_OF_TIME:
+		return "Time forfeit"
+	case WHITE_DISCONNECT_TIMEOUT, BLACK_DISCONNECT_TIMEOUT, DRAW_BY_TIMEOUT:
+		return "Abandoned"
+	case ADJOURNMENT_EXPIRED:
+		return "Adjudication"
+	}
+	if m.game.outcome() == chess.NoOutcome {
+		return "Unterminated"
+	}
+	return "Normal"
+}
+
+func teamName(team *team) string {
+	names := make([]string, 0, len(team.players))
+	for _, player := range team.players {
+		name := player.Username
+		if name == "" {
+			name = player.Id
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " & ")
+}
@@ -1,49 +1,91 @@
 package server
 
 import (
+	"errors"
+	"slices"
 	"sync"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/notnil/chess"
 )
 
+// connection is the write side of a player's socket.
+// A plain websocket is used for regular games, a simul host gets a board-scoped wrapper.
+type connection interface {
+	WriteJSON(v interface{}) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+}
+
 type player struct {
-	Id            string
-	Rating        float64
-	RD            float64
-	NewRatings    []float64
-	NewRDs        []float64
-	Conn          *websocket.Conn
-	Side          Side
-	Status        Status
-	Clock         time.Duration
-	TurnStartedAt time.Time
+	Id         string
+	Username   string
+	Rating     float64
+	RD         float64
+	NewRatings []float64
+	NewRDs     []float64
+	Side       Side
+	Role       Role
+	Status     Status
+
+	// Glicko-2 volatility now and after a win, a draw and a loss
+	Volatility      float64
+	NewVolatilities []float64
+	// Provisional players are guests, rated in casual games too
+	Provisional bool
+
+	// conns holds every socket the player has open for the match,
+	// only the primary one may submit moves
+	conns    []connection
+	versions map[connection]int
+	primary  connection
+	// pendingPrimary is the socket waiting for the primary device to hand the moves over
+	pendingPrimary connection
+
+	// mutedIds holds the players whose chat is hidden from this player
+	mutedIds   map[string]bool
+	chatSentAt []time.Time
 
 	mu *sync.Mutex
 }
 
+type deviceStatusResponse struct {
+	Type    string `json:"type"`
+	Primary bool   `json:"primary"`
+	Devices int    `json:"devices"`
+}
+
+type primaryDeviceRequestResponse struct {
+	Type    string `json:"type"`
+	Devices int    `json:"devices"`
+}
+
 func newPlayer(
-	conn *websocket.Conn,
 	playerId string,
+	username string,
 	side Side,
-	clock time.Duration,
+	role Role,
 	rating float64,
 	rd float64,
 	newRatings []float64,
 	newRDs []float64,
+	volatility float64,
+	newVolatilities []float64,
 ) player {
 	player := player{
-		Id:         playerId,
-		Rating:     rating,
-		RD:         rd,
-		NewRatings: newRatings,
-		NewRDs:     newRDs,
-		Conn:       conn,
-		Side:       side,
-		Status:     INIT,
-		Clock:      clock,
-		mu:         new(sync.Mutex),
+		Id:              playerId,
+		Username:        username,
+		Rating:          rating,
+		RD:              rd,
+		NewRatings:      newRatings,
+		NewRDs:          newRDs,
+		Volatility:      volatility,
+		NewVolatilities: newVolatilities,
+		Side:            side,
+		Role:            role,
+		Status:          INIT,
+		versions:        make(map[connection]int),
+		mutedIds:        make(map[string]bool),
+		mu:              new(sync.Mutex),
 	}
 	return player
 }
@@ -55,39 +97,164 @@ func (p *player) color() chess.Color {
 	return chess.Black
 }
 
-func (p *player) updateClock(
-	timeTaken time.Duration,
-	lagForgiven time.Duration,
-	increment time.Duration,
-) {
-	p.Clock = p.Clock - timeTaken + lagForgiven + increment
+/*
+addConn method    registers a new socket for the player.
+The first socket becomes the primary device.
+*/
+func (p *player) addConn(conn connection, version int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.conns = append(p.conns, conn)
+	p.versions[conn] = version
+	if p.primary == nil {
+		p.primary = conn
+	}
+	p.Status = CONNECTED
+	p.notifyDevices()
 }
 
-func (p *player) setConn(conn *websocket.Conn) {
+/*
+removeConn method    unregisters a closed socket.
+If it was the primary device, the oldest remaining socket takes over.
+The player only counts as disconnected once the last socket is gone.
+*/
+func (p *player) removeConn(conn connection) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if conn == nil {
+	for i, c := range p.conns {
+		if c == conn {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			delete(p.versions, conn)
+			break
+		}
+	}
+	if p.pendingPrimary == conn {
+		p.pendingPrimary = nil
+	}
+	if p.primary == conn {
+		p.primary = nil
+		if len(p.conns) > 0 {
+			p.primary = p.conns[0]
+		}
+	}
+	if len(p.conns) == 0 {
 		p.Status = DISCONNECTED
-	} else {
-		p.Status = CONNECTED
+		return
+	}
+	p.notifyDevices()
+}
+
+/*
+requestPrimary method    asks the primary device to hand the moves over to another socket of the player.
+Only the primary device can confirm it, so a new login cannot take over the game by itself.
+*/
+func (p *player) requestPrimary(conn connection) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !slices.Contains(p.conns, conn) {
+		return false
+	}
+	if p.primary == conn {
+		return true
 	}
-	p.Conn = conn
+	p.pendingPrimary = conn
+	if p.primary != nil {
+		p.primary.WriteJSON(primaryDeviceRequestResponse{
+			Type:    "primaryDeviceRequest",
+			Devices: len(p.conns),
+		})
+	}
+	return true
 }
 
+// confirmPrimary method    hands the moves over to the requesting socket, only the primary device may confirm
+func (p *player) confirmPrimary(conn connection) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.primary == nil || p.primary != conn || p.pendingPrimary == nil {
+		return false
+	}
+	p.primary = p.pendingPrimary
+	p.pendingPrimary = nil
+	p.notifyDevices()
+	return true
+}
+
+func (p *player) isPrimary(conn connection) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.primary != nil && p.primary == conn
+}
+
+// status method    returns the connection status of the player
+func (p *player) status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Status
+}
+
+func (p *player) isConnected() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns) > 0
+}
+
+// notifyDevices method    must be called with the player lock held
+func (p *player) notifyDevices() {
+	for _, conn := range p.conns {
+		conn.WriteJSON(deviceStatusResponse{
+			Type:    "deviceStatus",
+			Primary: conn == p.primary,
+			Devices: len(p.conns),
+		})
+	}
+}
+
+// writeJson method    broadcasts the message to every socket of the player
 func (p *player) writeJson(msg interface{}) error {
+	if p == nil {
+		return nil
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if p == nil || p.Conn == nil {
+	var errs []error
+	for _, conn := range p.conns {
+		if err := conn.WriteJSON(p.formatFor(conn, msg)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// formatFor method    must be called with the player lock held
+func (p *player) formatFor(conn connection, msg interface{}) interface{} {
+	if versioned, ok := msg.(versionedMessage); ok {
+		return versioned.forVersion(p.versions[conn])
+	}
+	return msg
+}
+
+// writeJsonTo method    sends the message to a single socket of the player
+func (p *player) writeJsonTo(conn connection, msg interface{}) error {
+	if p == nil || conn == nil {
 		return nil
 	}
-	return p.Conn.WriteJSON(msg)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return conn.WriteJSON(p.formatFor(conn, msg))
 }
 
 func (p *player) writeControl(messageType int, data []byte, deadline time.Time) error {
+	if p == nil {
+		return nil
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if p == nil || p.Conn == nil {
-		return nil
+	var errs []error
+	for _, conn := range p.conns {
+		if err := conn.WriteControl(messageType, data, deadline); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return p.Conn.WriteControl(messageType, data, deadline)
+	return errors.Join(errs...)
 }
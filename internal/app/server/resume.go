@@ -0,0 +1,194 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chess-vn/slchess/pkg/logging"
+	"github.com/notnil/chess"
+	"go.uber.org/zap"
+)
+
+/*
+resumeSession struct    binds a resume token to the player and the in-memory match.
+The token stays valid while its socket is open and for ResumeTokenTTL after it drops.
+*/
+type resumeSession struct {
+	matchId   string
+	playerId  string
+	match     *Match
+	expiresAt time.Time
+}
+
+type resumeTokenResponse struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+	Ttl   string `json:"ttl"`
+}
+
+type resumeResponse struct {
+	Type     string         `json:"type"`
+	FromPly  int            `json:"fromPly"`
+	Ply      int            `json:"ply"`
+	Moves    []moveResponse `json:"moves"`
+	Clocks   []string       `json:"clocks"`
+	Statuses []string       `json:"statuses"`
+	Outcome  string         `json:"outcome"`
+	Method   string         `json:"method"`
+}
+
+// issueResumeToken method    hands a fresh resume token to the joining socket
+func (s *server) issueResumeToken(conn connection, match *Match, playerId string) string {
+	player, exist := match.getPlayerWithId(playerId)
+	if !exist {
+		return ""
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		logging.Error("failed to generate resume token", zap.Error(err))
+		return ""
+	}
+	token := hex.EncodeToString(buf)
+	s.pruneResumeTokens()
+	s.resumeTokens.Store(token, &resumeSession{
+		matchId:  match.id,
+		playerId: playerId,
+		match:    match,
+	})
+	player.writeJsonTo(conn, resumeTokenResponse{
+		Type:  "resumeToken",
+		Token: token,
+		Ttl:   s.cfg.ResumeTokenTTL.String(),
+	})
+	return token
+}
+
+// startResumeWindow method    starts the expiry of a token once its socket is gone
+func (s *server) startResumeWindow(token string) {
+	value, ok := s.resumeTokens.Load(token)
+	if !ok {
+		return
+	}
+	session := value.(*resumeSession)
+	s.resumeTokens.Store(token, &resumeSession{
+		matchId:   session.matchId,
+		playerId:  session.playerId,
+		match:     session.match,
+		expiresAt: time.Now().Add(s.cfg.ResumeTokenTTL),
+	})
+}
+
+/*
+consumeResumeToken method    looks up the resume token sent with the request.
+A token can only be used once and only while its match is still running on this server.
+*/
+func (s *server) consumeResumeToken(r *http.Request, matchId string) (*resumeSession, bool) {
+	token := r.URL.Query().Get("resumeToken")
+	if token == "" {
+		return nil, false
+	}
+	value, ok := s.resumeTokens.LoadAndDelete(token)
+	if !ok {
+		return nil, false
+	}
+	session := value.(*resumeSession)
+	if session.matchId != matchId ||
+		(!session.expiresAt.IsZero() && time.Now().After(session.expiresAt)) ||
+		session.match.isEnded() {
+		return nil, false
+	}
+	if _, loaded := s.matches.Load(matchId); !loaded {
+		return nil, false
+	}
+	return session, true
+}
+
+func (s *server) pruneResumeTokens() {
+	now := time.Now()
+	s.resumeTokens.Range(func(key, value any) bool {
+		session := value.(*resumeSession)
+		if (!session.expiresAt.IsZero() && now.After(session.expiresAt)) ||
+			session.match.isEnded() {
+			s.resumeTokens.Delete(key)
+		}
+		return true
+	})
+}
+
+// lastSeenPlyOf function    reads the last ply the client has seen, -1 when missing
+func lastSeenPlyOf(r *http.Request) int {
+	ply, err := strconv.Atoi(r.URL.Query().Get("lastPly"))
+	if err != nil || ply < 0 {
+		return -1
+	}
+	return ply
+}
+
+/*
+handlePlayerResume method    restores a dropped socket without the full join handshake.
+Only the moves played after the last seen ply are sent back,
+a client that is ahead of the server or did not send its ply gets a full sync.
+*/
+func (s *server) handlePlayerResume(
+	conn connection,
+	version int,
+	match *Match,
+	playerId string,
+	lastPly int,
+) {
+	player, exist := match.getPlayerWithId(playerId)
+	if !exist {
+		return
+	}
+	player.addConn(conn, version)
+	if match.resumedAdjournment &&
+		match.teams[0].status() == CONNECTED &&
+		match.teams[1].status() == CONNECTED {
+		s.resumeAdjournedMatch(match)
+	}
+
+	if lastPly < 0 || lastPly > match.currentPly() {
+		match.syncPlayer(player)
+	} else {
+		player.writeJsonTo(conn, match.missedEvents(lastPly))
+		match.sendFirstMoveCountdown(player)
+	}
+
+	logging.Info("player resumed",
+		zap.String("player_id", playerId),
+		zap.String("match_id", match.id),
+		zap.Int("last_ply", lastPly),
+	)
+
+	match.notifyAboutPlayerStatus(playerStatusResponse{
+		Type:     "playerStatus",
+		PlayerId: playerId,
+		Status:   player.status().String(),
+	})
+}
+
+// missedEvents method    lists the moves after the given ply along with the current clocks
+func (m *Match) missedEvents(lastPly int) resumeResponse {
+	g := m.game
+	moves := g.Moves()
+	resp := resumeResponse{
+		Type:     "resume",
+		FromPly:  lastPly,
+		Ply:      m.currentPly(),
+		Moves:    []moveResponse{},
+		Clocks:   m.liveClocks(),
+		Statuses: m.statuses(),
+		Outcome:  g.outcome().String(),
+		Method:   g.method(),
+	}
+	for i := lastPly; i < len(moves); i++ {
+		resp.Moves = append(resp.Moves, moveResponse{
+			Uci: chess.UCINotation{}.Encode(nil, moves[i]),
+			San: g.san(i),
+		})
+	}
+	return resp
+}
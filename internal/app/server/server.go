@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"crypto/rsa"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,14 +13,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/chess-vn/slchess/internal/app/adjourned"
 	"github.com/chess-vn/slchess/internal/aws/auth"
-	awsAuth "github.com/chess-vn/slchess/internal/aws/auth"
 	"github.com/chess-vn/slchess/internal/aws/compute"
 	"github.com/chess-vn/slchess/internal/aws/storage"
 	"github.com/chess-vn/slchess/internal/domains/entities"
 	"github.com/chess-vn/slchess/pkg/logging"
 	"github.com/chess-vn/slchess/pkg/utils"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 )
@@ -32,13 +30,18 @@ type server struct {
 
 	cfg          Config
 	matches      sync.Map
-	totalMatches atomic.Int32
-	mu           *sync.Mutex
-
-	cognitoPublicKeys map[string]*rsa.PublicKey
-	storageClient     *storage.Client
-	computeClient     *compute.Client
-	lambdaClient      *lambda.Client
+	simuls       sync.Map
+	resumeTokens sync.Map
+	// Matches removed from memory, by the time they were removed
+	removedMatches sync.Map
+	totalMatches   atomic.Int32
+	mu             *sync.Mutex
+
+	authenticator Authenticator
+	storageClient *storage.Client
+	computeClient *compute.Client
+	lambdaClient  *lambda.Client
+	chatFilter    ChatFilter
 
 	protectionTimer *utils.Timer
 }
@@ -51,12 +54,7 @@ type payload struct {
 
 func NewServer() *server {
 	cfg := NewConfig()
-	tokenSigningKeyUrl := fmt.Sprintf(
-		"https://cognito-idp.%s.amazonaws.com/%s/.well-known/jwks.json",
-		cfg.AwsRegion,
-		cfg.CognitoUserPoolId,
-	)
-	cognitoPublicKeys, err := awsAuth.LoadCognitoPublicKeys(tokenSigningKeyUrl)
+	authenticator, err := newAuthenticator(cfg)
 	if err != nil {
 		panic(err)
 	}
@@ -70,9 +68,9 @@ func NewServer() *server {
 				return true // Allow all origins
 			},
 		},
-		mu:                new(sync.Mutex),
-		cfg:               cfg,
-		cognitoPublicKeys: cognitoPublicKeys,
+		mu:            new(sync.Mutex),
+		cfg:           cfg,
+		authenticator: authenticator,
 		storageClient: storage.NewClient(
 			dynamodb.NewFromConfig(awsCfg),
 		),
@@ -82,11 +80,17 @@ func NewServer() *server {
 			nil,
 		),
 		lambdaClient: lambda.NewFromConfig(awsCfg),
+		chatFilter:   NewWordChatFilter(cfg.ChatBannedWords),
 	}
 	srv.resetProtectionTimer(cfg.IdleTimeout)
 	return srv
 }
 
+// UseChatFilter method    replaces the filter applied to player chat of new matches
+func (s *server) UseChatFilter(filter ChatFilter) {
+	s.chatFilter = filter
+}
+
 // Start method    starts the game server
 func (s *server) Start() error {
 	// Server status
@@ -101,12 +105,22 @@ func (s *server) Start() error {
 
 	// Websocket
 	http.HandleFunc("/game/{matchId}", func(w http.ResponseWriter, r *http.Request) {
-		playerId, err := s.auth(r)
-		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
-			w.Write([]byte(err.Error()))
-			logging.Error("failed to auth: %w", zap.Error(err))
-			return
+		matchId := r.PathValue("matchId")
+
+		// A valid resume token skips both the jwt validation and the match lookup
+		session, resumed := s.consumeResumeToken(r, matchId)
+		var playerId string
+		if resumed {
+			playerId = session.playerId
+		} else {
+			var err error
+			playerId, err = s.auth(r)
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(err.Error()))
+				logging.Error("failed to auth: %w", zap.Error(err))
+				return
+			}
 		}
 
 		conn, err := s.upgrader.Upgrade(w, r, nil)
@@ -119,21 +133,28 @@ func (s *server) Start() error {
 		}
 		defer conn.Close()
 
-		matchId := r.PathValue("matchId")
-		match, err := s.loadMatch(matchId)
-		if err != nil {
-			logging.Info("failed to load match", zap.String("error", err.Error()))
-			conn.WriteControl(
-				websocket.CloseMessage,
-				websocket.FormatCloseMessage(
-					websocket.CloseNormalClosure,
-					"match expired",
-				),
-				time.Now().Add(5*time.Second),
-			)
-			return
+		var match *Match
+		if resumed {
+			match = session.match
+			s.handlePlayerResume(conn, gameStateVersionOf(r), match, playerId, lastSeenPlyOf(r))
+		} else {
+			match, err = s.loadMatchFromTicket(matchId, r.URL.Query().Get("ticket"))
+			if err != nil {
+				logging.Info("failed to load match", zap.String("error", err.Error()))
+				conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(
+						websocket.CloseNormalClosure,
+						"match expired",
+					),
+					time.Now().Add(5*time.Second),
+				)
+				return
+			}
+			s.handlePlayerJoin(conn, gameStateVersionOf(r), match, playerId)
 		}
-		s.handlePlayerJoin(conn, match, playerId)
+		resumeToken := s.issueResumeToken(conn, match, playerId)
+		defer s.startResumeWindow(resumeToken)
 
 		for {
 			_, message, err := conn.ReadMessage()
@@ -156,15 +177,91 @@ func (s *server) Start() error {
 						zap.Error(err),
 					)
 				}
-				s.handlePlayerDisconnect(match, playerId)
+				s.handlePlayerDisconnect(conn, match, playerId)
+				break
+			}
+
+			var payload payload
+			if err := json.Unmarshal(message, &payload); err != nil {
+				// Stop processing, the next read fails and runs the disconnect handling
+				conn.Close()
+				continue
+			}
+			s.handleWebSocketMessage(conn, playerId, match, payload)
+		}
+	})
+
+	// Simul host websocket, multiplexing every board of the exhibition
+	http.HandleFunc("/simul/{simulId}", func(w http.ResponseWriter, r *http.Request) {
+		playerId, err := s.auth(r)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(err.Error()))
+			logging.Error("failed to auth: %w", zap.Error(err))
+			return
+		}
+
+		simulId := r.PathValue("simulId")
+		session, err := s.loadSimulSession(context.Background(), simulId)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(err.Error()))
+			logging.Info("failed to load simul", zap.String("error", err.Error()))
+			return
+		}
+		if session.hostId != playerId {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		conn, err := s.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logging.Error(
+				"failed to upgrade connection",
+				zap.String("error", err.Error()),
+			)
+			return
+		}
+		defer conn.Close()
+		session.setConn(conn)
+
+		for _, matchId := range session.matchIds {
+			match, err := s.loadMatch(matchId)
+			if err != nil {
+				logging.Info(
+					"failed to load simul board",
+					zap.String("match_id", matchId),
+					zap.String("error", err.Error()),
+				)
+				continue
+			}
+			s.handlePlayerJoin(session.connFor(matchId), gameStateVersionOf(r), match, playerId)
+			match.updateSimul(match.isEnded())
+		}
+		session.sendDashboard()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				logging.Info(
+					"simul host disconnected",
+					zap.String("simul_id", simulId),
+					zap.Error(err),
+				)
+				session.setConn(nil)
+				for _, match := range session.activeMatches() {
+					s.handlePlayerDisconnect(session.connFor(match.id), match, playerId)
+				}
 				break
 			}
 
 			var payload payload
 			if err := json.Unmarshal(message, &payload); err != nil {
+				// Stop processing, the next read fails and runs the disconnect handling
 				conn.Close()
+				continue
 			}
-			s.handleWebSocketMessage(playerId, match, payload)
+			s.handleSimulMessage(playerId, session, payload)
 		}
 	})
 	logging.Info("websocket server started", zap.String("port", s.cfg.Port))
@@ -173,27 +270,16 @@ func (s *server) Start() error {
 
 // mustAuth method    authenticates and extract userId
 func (s *server) auth(r *http.Request) (string, error) {
-	token := r.Header.Get("Authorization")
-	if token == "" {
-		return "", fmt.Errorf("no authorization")
-	}
-	validToken, err := auth.ValidateJwt(token, s.cognitoPublicKeys)
-	if err != nil || !validToken.Valid {
-		return "", fmt.Errorf("invalid token: %w", err)
-	}
-	mapClaims, ok := validToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", fmt.Errorf("invalid map claims")
-	}
-	v, ok := mapClaims["sub"]
-	if !ok {
-		return "", fmt.Errorf("user id not found")
-	}
-	userId, ok := v.(string)
-	if !ok {
-		return "", fmt.Errorf("invalid user id")
+	identity, err := s.authenticator.Authenticate(r)
+	if err != nil {
+		return "", err
 	}
-	return userId, nil
+	return identity.UserId, nil
+}
+
+// UseAuthenticator method    replaces the authenticator picked from the config
+func (s *server) UseAuthenticator(authenticator Authenticator) {
+	s.authenticator = authenticator
 }
 
 /*
@@ -213,10 +299,25 @@ func (s *server) loadMatch(matchId string) (*Match, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get match config: %w", err)
 	}
+	config.Casual = activeMatch.Casual
+
+	var simul *simulSession
+	if activeMatch.SimulId != "" {
+		simul, err = s.loadSimulSession(ctx, activeMatch.SimulId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load simul session: %w", err)
+		}
+		if simul.clockMode == entities.SimulClockNone {
+			config.Untimed = true
+			config.MatchDuration = untimedMatchDuration
+		}
+	}
 
-	// Check if match is expired
-	if (activeMatch.StartedAt == nil && time.Since(activeMatch.CreatedAt) > 2*time.Minute) ||
-		(activeMatch.StartedAt != nil && time.Since(*activeMatch.StartedAt) > 2*config.MatchDuration+2*time.Minute) {
+	// Check if match is expired, adjourned matches are only bound by their deadline
+	adjournment := activeMatch.Adjournment
+	if adjournment == nil &&
+		((activeMatch.StartedAt == nil && time.Since(activeMatch.CreatedAt) > 2*time.Minute) ||
+			(activeMatch.StartedAt != nil && time.Since(*activeMatch.StartedAt) > 2*config.MatchDuration+2*time.Minute)) {
 		err := s.removeExpiredMatch(activeMatch)
 		if err != nil {
 			return nil, fmt.Errorf("failed to remove expired match: %w", err)
@@ -224,6 +325,33 @@ func (s *server) loadMatch(matchId string) (*Match, error) {
 		return nil, fmt.Errorf("match expired")
 	}
 
+	match, err := s.getOrRestoreMatch(ctx, activeMatch, config, simul)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nobody resumed the adjourned match in time, score it right away.
+	// Ending the match invokes the end game handler, so it must not hold the server lock.
+	if match.resumedAdjournment && adjourned.Expired(adjournment, time.Now()) {
+		match.resumedAdjournment = false
+		match.game.adjournmentExpired()
+		match.end()
+		return nil, ErrAdjournExpired
+	}
+
+	return match, nil
+}
+
+// getOrRestoreMatch method    returns the match in memory, or restores it from its saved state
+func (s *server) getOrRestoreMatch(
+	ctx context.Context,
+	activeMatch entities.ActiveMatch,
+	config MatchConfig,
+	simul *simulSession,
+) (*Match, error) {
+	matchId := activeMatch.MatchId
+	adjournment := activeMatch.Adjournment
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -236,91 +364,203 @@ func (s *server) loadMatch(matchId string) (*Match, error) {
 		}
 		return nil, ErrFailedToLoadMatch
 	} else {
-		matchStates, _, err := s.storageClient.FetchMatchStates(
-			ctx,
-			matchId,
-			nil,
-			1,
-			false,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch match states: %w", err)
-		}
-
-		var clock1 time.Duration
-		var clock2 time.Duration
+		players := playersOf(activeMatch)
+		mode := parseTeamMode(activeMatch.TeamMode)
 
-		player1 := newPlayer(
-			nil,
-			activeMatch.Player1.Id,
-			WHITE_SIDE,
-			clock1,
-			activeMatch.Player1.Rating,
-			activeMatch.Player1.RD,
-			activeMatch.Player1.NewRatings,
-			activeMatch.Player1.NewRDs,
-		)
-		player2 := newPlayer(
-			nil,
-			activeMatch.Player2.Id,
-			BLACK_SIDE,
-			clock2,
-			activeMatch.Player2.Rating,
-			activeMatch.Player2.RD,
-			activeMatch.Player2.NewRatings,
-			activeMatch.Player1.NewRDs,
+		var (
+			matchStates []entities.MatchState
+			err         error
 		)
+		// Load test servers never save states
+		if s.cfg.PersistMatches {
+			matchStates, _, err = s.storageClient.FetchMatchStates(
+				ctx,
+				matchId,
+				nil,
+				1,
+				false,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch match states: %w", err)
+			}
+		}
 
 		var match *Match
-		if len(matchStates) > 0 {
-			player1.Clock, _ = time.ParseDuration(matchStates[0].PlayerStates[0].Clock)
-			player2.Clock, _ = time.ParseDuration(matchStates[0].PlayerStates[1].Clock)
+		if adjournment != nil {
+			match, err = s.restoreAdjournedMatch(
+				matchId,
+				mode,
+				players,
+				config,
+				*adjournment,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore adjourned match: %w", err)
+			}
+		} else if len(matchStates) > 0 {
+			clock1, _ := time.ParseDuration(matchStates[0].PlayerStates[0].Clock)
+			clock2, _ := time.ParseDuration(matchStates[0].PlayerStates[1].Clock)
 			match, err = s.resumeMatch(
 				matchId,
-				player1,
-				player2,
+				mode,
+				players,
+				[]time.Duration{clock1, clock2},
 				config,
+				simul,
 				matchStates[0].GameState,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("failed to resume match: %w", err)
 			}
 		} else {
-			player1.Clock = config.MatchDuration
-			player2.Clock = config.MatchDuration
-			match = s.newMatch(matchId, player1, player2, config)
+			match = s.newMatch(
+				matchId,
+				mode,
+				players,
+				[]time.Duration{config.MatchDuration, config.MatchDuration},
+				config,
+				simul,
+			)
+		}
+		if simul != nil {
+			simul.addBoard(match)
 		}
 		logging.Info(
 			"match loaded",
 			zap.String("match_id", matchId),
-			zap.String("player1_id", player1.Id),
-			zap.String("player2_id", player2.Id),
+			zap.String("player1_id", activeMatch.Player1.Id),
+			zap.String("player2_id", activeMatch.Player2.Id),
+			zap.Int("total_players", len(players)),
 		)
 
-		s.matches.Store(matchId, match)
-		s.totalMatches.Add(1)
-		s.resetProtectionTimer(2*match.cfg.MatchDuration + 5*time.Minute)
-
+		s.addMatch(match)
 		return match, nil
 	}
 }
 
+/*
+loadMatchFromTicket method    starts the match straight from a signed match ticket.
+The verified ticket replaces the active match lookup, a saved state of the match still takes precedence
+over the claims. The active match is only read for restore tickets, simul boards, tickets that fail
+verification and matches that may have moved on since the ticket was issued: those removed from this
+server and those past the first move window.
+*/
+func (s *server) loadMatchFromTicket(matchId, ticket string) (*Match, error) {
+	if ticket == "" {
+		return s.loadMatch(matchId)
+	}
+	claims, err := auth.ParseMatchTicket(ticket, s.cfg.MatchTicketSecret)
+	if err != nil {
+		logging.Info("falling back to active match lookup", zap.Error(err))
+		return s.loadMatch(matchId)
+	}
+	if claims.MatchId != matchId || claims.Restore || claims.SimulId != "" {
+		return s.loadMatch(matchId)
+	}
+
+	activeMatch := claims.ActiveMatch()
+	config, err := configForGameMode(activeMatch.GameMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match config: %w", err)
+	}
+	config.Casual = activeMatch.Casual
+
+	// An unstarted match expires after 2 minutes, an older one may have started, ended or been adjourned
+	if _, loaded := s.matches.Load(matchId); !loaded &&
+		(s.wasRemoved(matchId) || time.Since(activeMatch.CreatedAt) > 2*time.Minute) {
+		return s.loadMatch(matchId)
+	}
+	// A match saved by another server resumes from its last state, the claims only start a new one
+	return s.getOrRestoreMatch(context.TODO(), activeMatch, config, nil)
+}
+
+// wasRemoved method    reports whether the match left this server while its tickets may still be valid
+func (s *server) wasRemoved(matchId string) bool {
+	s.removedMatches.Range(func(key, value any) bool {
+		if time.Since(value.(time.Time)) > auth.MatchTicketTTL {
+			s.removedMatches.Delete(key)
+		}
+		return true
+	})
+	_, removed := s.removedMatches.Load(matchId)
+	return removed
+}
+
+// addMatch method    must be called with the server lock held
+func (s *server) addMatch(match *Match) {
+	s.matches.Store(match.id, match)
+	s.totalMatches.Add(1)
+	s.resetProtectionTimer(2*match.cfg.MatchDuration + 5*time.Minute)
+}
+
+// playersOf function    creates the in-game players of an active match
+func playersOf(activeMatch entities.ActiveMatch) []*player {
+	players := make([]*player, 0, 2+len(activeMatch.Teammates))
+	for _, p := range activeMatch.Players() {
+		side := WHITE_SIDE
+		if p.Team == 1 {
+			side = BLACK_SIDE
+		}
+		player := newPlayer(
+			p.Id,
+			p.Username,
+			side,
+			Role(p.Role),
+			p.Rating,
+			p.RD,
+			p.NewRatings,
+			p.NewRDs,
+			p.Volatility,
+			p.NewVolatilities,
+		)
+		player.Provisional = p.Provisional
+		players = append(players, &player)
+	}
+	return players
+}
+
+// loadSimulSession method    returns the in-memory session of a simul, loading it on first use
+func (s *server) loadSimulSession(ctx context.Context, simulId string) (*simulSession, error) {
+	if value, loaded := s.simuls.Load(simulId); loaded {
+		return value.(*simulSession), nil
+	}
+	simul, err := s.storageClient.GetSimul(ctx, simulId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get simul: %w", err)
+	}
+	session, err := newSimulSession(simul)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create simul session: %w", err)
+	}
+	session.endHandler = s.handleSimulEnd
+	value, _ := s.simuls.LoadOrStore(simulId, session)
+	return value.(*simulSession), nil
+}
+
 func (s *server) newMatch(
 	matchId string,
-	player1,
-	player2 player,
+	mode TeamMode,
+	players []*player,
+	clocks []time.Duration,
 	config MatchConfig,
+	simul *simulSession,
 ) *Match {
 	match := &Match{
 		id:               matchId,
+		mode:             mode,
 		game:             newGame(),
-		players:          []*player{&player1, &player2},
+		players:          players,
+		teams:            groupTeams(players, clocks),
 		moveCh:           make(chan move),
+		done:             make(chan struct{}),
 		cfg:              config,
+		simul:            simul,
 		abortGameHandler: s.handleAbortGame,
 		endGameHandler:   s.handleEndGame,
 		saveGameHandler:  s.handleSaveGame,
+		chatFilter:       s.chatFilter,
 	}
+	match.adjournGameHandler = s.handleAdjournGame
 	// Timeout to cancel match if first move is not made
 	match.setTimer(config.CancelTimeout)
 	go match.start()
@@ -329,9 +569,11 @@ func (s *server) newMatch(
 
 func (s *server) resumeMatch(
 	matchId string,
-	player1,
-	player2 player,
+	mode TeamMode,
+	players []*player,
+	clocks []time.Duration,
 	config MatchConfig,
+	simul *simulSession,
 	gameState string,
 ) (*Match, error) {
 	game, err := restoreGame(gameState)
@@ -340,22 +582,102 @@ func (s *server) resumeMatch(
 	}
 	match := &Match{
 		id:               matchId,
+		mode:             mode,
 		game:             game,
-		players:          []*player{&player1, &player2},
+		players:          players,
+		teams:            groupTeams(players, clocks),
 		moveCh:           make(chan move),
+		done:             make(chan struct{}),
 		cfg:              config,
+		simul:            simul,
 		abortGameHandler: s.handleAbortGame,
 		endGameHandler:   s.handleEndGame,
 		saveGameHandler:  s.handleSaveGame,
+		chatFilter:       s.chatFilter,
 	}
+	match.adjournGameHandler = s.handleAdjournGame
 	// Timeout to cancel match if first move is not made
 	match.setTimer(config.CancelTimeout)
 	go match.start()
 	return match, nil
 }
 
+/*
+restoreAdjournedMatch method    rebuilds an adjourned match from its full move list.
+The clocks stay frozen until every team has come back.
+*/
+func (s *server) restoreAdjournedMatch(
+	matchId string,
+	mode TeamMode,
+	players []*player,
+	config MatchConfig,
+	adjournment entities.Adjournment,
+) (*Match, error) {
+	match, err := adjournedMatch(matchId, mode, players, config, adjournment)
+	if err != nil {
+		return nil, err
+	}
+	match.abortGameHandler = s.handleAbortGame
+	match.endGameHandler = s.handleEndGame
+	match.saveGameHandler = s.handleSaveGame
+	match.adjournGameHandler = s.handleAdjournGame
+	match.chatFilter = s.chatFilter
+	// Timeout to adjourn the match again if the opponent does not come back
+	match.setTimer(config.DisconnectTimeout)
+	go match.start()
+	return match, nil
+}
+
+// adjournedMatch function    rebuilds an adjourned match from its saved moves and clocks, without starting it
+func adjournedMatch(
+	matchId string,
+	mode TeamMode,
+	players []*player,
+	config MatchConfig,
+	adjournment entities.Adjournment,
+) (*Match, error) {
+	game, err := restoreGameFromMoves(adjournment.Moves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore game: %w", err)
+	}
+	moveClocks, clocks, err := adjourned.Clocks(adjournment)
+	if err != nil {
+		return nil, err
+	}
+	for i, clock := range moveClocks {
+		game.moves[i].clock = clock
+	}
+	return &Match{
+		id:                 matchId,
+		mode:               mode,
+		game:               game,
+		players:            players,
+		teams:              groupTeams(players, clocks),
+		moveCh:             make(chan move),
+		done:               make(chan struct{}),
+		cfg:                config,
+		adjournedAt:        adjournment.AdjournedAt,
+		resumedAdjournment: true,
+	}, nil
+}
+
+// groupTeams function    groups players into the white and black team with their clocks
+func groupTeams(players []*player, clocks []time.Duration) []*team {
+	white := newTeam(WHITE_SIDE, clocks[0])
+	black := newTeam(BLACK_SIDE, clocks[1])
+	for _, player := range players {
+		if player.Side == WHITE_SIDE {
+			white.players = append(white.players, player)
+		} else {
+			black.players = append(black.players, player)
+		}
+	}
+	return []*team{white, black}
+}
+
 func (s *server) removeMatch(matchId string) {
 	s.matches.Delete(matchId)
+	s.removedMatches.Store(matchId, time.Now())
 	total := s.totalMatches.Add(-1)
 	if total <= 0 {
 		s.skipProtectionTimer()
@@ -417,13 +739,11 @@ func (s *server) removeExpiredMatch(activeMatch entities.ActiveMatch) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete match: %w", err)
 	}
-	err = s.storageClient.DeleteUserMatch(ctx, activeMatch.Player1.Id)
-	if err != nil {
-		return fmt.Errorf("failed to delete user match: %w", err)
-	}
-	err = s.storageClient.DeleteUserMatch(ctx, activeMatch.Player2.Id)
-	if err != nil {
-		return fmt.Errorf("failed to delete user match: %w", err)
+	for _, player := range activeMatch.Players() {
+		err = s.storageClient.DeleteUserMatch(ctx, player.Id)
+		if err != nil {
+			return fmt.Errorf("failed to delete user match: %w", err)
+		}
 	}
 	err = s.storageClient.DeleteSpectatorConversation(ctx, activeMatch.MatchId)
 	if err != nil {
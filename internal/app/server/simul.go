@@ -0,0 +1,328 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/chess-vn/slchess/pkg/logging"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Boards of an untimed simul are only cut off after this long without a move
+const untimedMatchDuration = 3 * time.Hour
+
+/*
+simulSession multiplexes every board of a simultaneous exhibition
+over the host's single socket.
+*/
+type simulSession struct {
+	id        string
+	hostId    string
+	clockMode string
+	matchIds  []string
+
+	conn      *websocket.Conn
+	boardConn map[string]*simulConn
+	boards    map[string]*simulBoard
+	order     []string
+	clock     *simulClock
+	ended     bool
+	writeMu   sync.Mutex
+	mu        sync.Mutex
+
+	endHandler func(*simulSession)
+}
+
+type simulBoard struct {
+	match   *Match
+	waiting bool
+	ended   bool
+}
+
+// simulClock is the host clock shared by every board.
+// It runs while at least one board is waiting for the host's move.
+type simulClock struct {
+	remaining    time.Duration
+	runningSince time.Time
+	running      bool
+	timer        *time.Timer
+	onFlag       func()
+}
+
+// simulConn is the host connection scoped to a single board
+type simulConn struct {
+	session *simulSession
+	matchId string
+}
+
+type simulBoardResponse struct {
+	Type    string      `json:"type"`
+	MatchId string      `json:"matchId"`
+	Message interface{} `json:"message"`
+}
+
+type simulBoardClosedResponse struct {
+	Type    string `json:"type"`
+	MatchId string `json:"matchId"`
+	Reason  string `json:"reason"`
+}
+
+type simulDashboardResponse struct {
+	Type           string   `json:"type"`
+	SimulId        string   `json:"simulId"`
+	WaitingBoards  []string `json:"waitingBoards"`
+	TotalBoards    int      `json:"totalBoards"`
+	FinishedBoards int      `json:"finishedBoards"`
+	HostClock      string   `json:"hostClock,omitempty"`
+}
+
+func newSimulSession(simul entities.Simul) (*simulSession, error) {
+	session := &simulSession{
+		id:        simul.SimulId,
+		hostId:    simul.HostId,
+		clockMode: simul.ClockMode,
+		matchIds:  simul.MatchIds,
+		boardConn: make(map[string]*simulConn),
+		boards:    make(map[string]*simulBoard),
+	}
+	if simul.ClockMode == entities.SimulClockShared {
+		hostClock, err := time.ParseDuration(simul.HostClock)
+		if err != nil {
+			return nil, err
+		}
+		session.clock = &simulClock{
+			remaining: hostClock,
+			onFlag:    session.flagHost,
+		}
+	}
+	return session, nil
+}
+
+func (c *simulConn) WriteJSON(v interface{}) error {
+	return c.session.writeJson(simulBoardResponse{
+		Type:    "simulBoard",
+		MatchId: c.matchId,
+		Message: v,
+	})
+}
+
+// WriteControl method    turns a board close into a message so the host socket stays open
+func (c *simulConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	if messageType != websocket.CloseMessage {
+		return nil
+	}
+	var reason string
+	if len(data) > 2 {
+		reason = string(data[2:])
+	}
+	return c.session.writeJson(simulBoardClosedResponse{
+		Type:    "simulBoardClosed",
+		MatchId: c.matchId,
+		Reason:  reason,
+	})
+}
+
+// connFor method    returns the board-scoped connection, the same value on every call
+func (s *simulSession) connFor(matchId string) connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, exist := s.boardConn[matchId]
+	if !exist {
+		conn = &simulConn{
+			session: s,
+			matchId: matchId,
+		}
+		s.boardConn[matchId] = conn
+	}
+	return conn
+}
+
+func (s *simulSession) setConn(conn *websocket.Conn) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn = conn
+}
+
+func (s *simulSession) writeJson(msg interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.WriteJSON(msg)
+}
+
+func (s *simulSession) addBoard(match *Match) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exist := s.boards[match.id]; exist {
+		return
+	}
+	s.boards[match.id] = &simulBoard{match: match}
+	s.order = append(s.order, match.id)
+}
+
+func (s *simulSession) getMatch(matchId string) (*Match, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	board, exist := s.boards[matchId]
+	if !exist {
+		return nil, false
+	}
+	return board.match, true
+}
+
+// activeMatches method    returns the boards that are still being played
+func (s *simulSession) activeMatches() []*Match {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matches := make([]*Match, 0, len(s.boards))
+	for _, matchId := range s.order {
+		if board := s.boards[matchId]; !board.ended {
+			matches = append(matches, board.match)
+		}
+	}
+	return matches
+}
+
+/*
+updateBoard method    records whether a board waits for the host and pushes the dashboard.
+It is called by the board itself after every state change.
+*/
+func (s *simulSession) updateBoard(matchId string, waiting, ended bool) {
+	s.mu.Lock()
+	board, exist := s.boards[matchId]
+	if !exist {
+		s.mu.Unlock()
+		return
+	}
+	board.waiting = waiting
+	board.ended = ended
+
+	if s.clock != nil {
+		if s.countWaiting() > 0 {
+			s.clock.run()
+		} else {
+			s.clock.pause()
+		}
+	}
+
+	allEnded := len(s.boards) > 0
+	for _, board := range s.boards {
+		if !board.ended {
+			allEnded = false
+			break
+		}
+	}
+	shouldEnd := allEnded && !s.ended
+	if shouldEnd {
+		s.ended = true
+		if s.clock != nil {
+			s.clock.pause()
+		}
+	}
+	dashboard := s.dashboard()
+	s.mu.Unlock()
+
+	if err := s.writeJson(dashboard); err != nil {
+		logging.Error("couldn't send simul dashboard", zap.String("simul_id", s.id))
+	}
+	if shouldEnd && s.endHandler != nil {
+		s.endHandler(s)
+	}
+}
+
+func (s *simulSession) sendDashboard() {
+	s.mu.Lock()
+	dashboard := s.dashboard()
+	s.mu.Unlock()
+	if err := s.writeJson(dashboard); err != nil {
+		logging.Error("couldn't send simul dashboard", zap.String("simul_id", s.id))
+	}
+}
+
+// hostClock method    returns the live value of the shared host clock
+func (s *simulSession) hostClock() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clock == nil {
+		return 0
+	}
+	return s.clock.current()
+}
+
+// flagHost method    ends every unfinished board as lost on time by the host
+func (s *simulSession) flagHost() {
+	logging.Info("simul host out of time", zap.String("simul_id", s.id))
+	for _, match := range s.activeMatches() {
+		if _, exist := match.getPlayerWithId(s.hostId); !exist {
+			continue
+		}
+		// The match ends itself from its own event loop, a board that ended meanwhile drops the flag
+		match.processGameControl(s.hostId, FLAG)
+	}
+}
+
+// dashboard method    must be called with the session lock held
+func (s *simulSession) dashboard() simulDashboardResponse {
+	resp := simulDashboardResponse{
+		Type:          "simulDashboard",
+		SimulId:       s.id,
+		WaitingBoards: []string{},
+		TotalBoards:   len(s.boards),
+	}
+	for _, matchId := range s.order {
+		board := s.boards[matchId]
+		if board.ended {
+			resp.FinishedBoards++
+		} else if board.waiting {
+			resp.WaitingBoards = append(resp.WaitingBoards, matchId)
+		}
+	}
+	if s.clock != nil {
+		resp.HostClock = s.clock.current().String()
+	}
+	return resp
+}
+
+func (s *simulSession) countWaiting() int {
+	count := 0
+	for _, board := range s.boards {
+		if board.waiting && !board.ended {
+			count++
+		}
+	}
+	return count
+}
+
+func (c *simulClock) current() time.Duration {
+	if !c.running {
+		return c.remaining
+	}
+	if current := c.remaining - time.Since(c.runningSince); current > 0 {
+		return current
+	}
+	return 0
+}
+
+func (c *simulClock) run() {
+	if c.running {
+		return
+	}
+	c.running = true
+	c.runningSince = time.Now()
+	c.timer = time.AfterFunc(c.remaining, c.onFlag)
+}
+
+func (c *simulClock) pause() {
+	if !c.running {
+		return
+	}
+	c.remaining = c.current()
+	c.running = false
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+func TestFlagHostWhileBoardEnds(t *testing.T) {
+	session, err := newSimulSession(entities.Simul{
+		SimulId:   "simul",
+		HostId:    "host",
+		ClockMode: entities.SimulClockNone,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := make([]*Match, 0, 50)
+	for i := 0; i < 50; i++ {
+		match := testMatch(t, true,
+			testPlayer("host", 1500, 1500, 1500, 1500),
+			testPlayer("guest", 1500, 1500, 1500, 1500),
+		)
+		match.id = fmt.Sprintf("board-%d", i)
+		match.simul = session
+		session.addBoard(match)
+		go match.start()
+		matches = append(matches, match)
+	}
+
+	// Every board ends on its own while the host clock runs out
+	var wg sync.WaitGroup
+	for _, match := range matches {
+		wg.Add(1)
+		go func(match *Match) {
+			defer wg.Done()
+			match.end()
+		}(match)
+	}
+	flagged := make(chan struct{})
+	go func() {
+		session.flagHost()
+		close(flagged)
+	}()
+	select {
+	case <-flagged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("flagging the host blocked on an ended board")
+	}
+	wg.Wait()
+	for _, match := range matches {
+		if !match.isEnded() {
+			t.Errorf("board %s did not end", match.id)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+type (
+	TeamMode string
+	Role     string
+)
+
+const (
+	STANDARD       TeamMode = "STANDARD"
+	HAND_AND_BRAIN TeamMode = "HAND_AND_BRAIN"
+
+	NO_ROLE Role = ""
+	HAND    Role = "HAND"
+	BRAIN   Role = "BRAIN"
+)
+
+// team groups the players sharing one side of the board.
+// The clock belongs to the team so every member sees the same time.
+type team struct {
+	Side          Side
+	Clock         time.Duration
+	TurnStartedAt time.Time
+
+	players        []*player
+	pieceSelection chess.PieceType
+}
+
+type teamMessageResponse struct {
+	Type      string `json:"type"`
+	PlayerId  string `json:"playerId"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type pieceSelectionResponse struct {
+	Type     string `json:"type"`
+	PlayerId string `json:"playerId"`
+	Piece    string `json:"piece"`
+}
+
+func newTeam(side Side, clock time.Duration, players ...*player) *team {
+	return &team{
+		Side:           side,
+		Clock:          clock,
+		players:        players,
+		pieceSelection: chess.NoPieceType,
+	}
+}
+
+func parseTeamMode(mode string) TeamMode {
+	switch TeamMode(mode) {
+	case HAND_AND_BRAIN:
+		return HAND_AND_BRAIN
+	default:
+		return STANDARD
+	}
+}
+
+func (t *team) updateClock(
+	timeTaken time.Duration,
+	lagForgiven time.Duration,
+	increment time.Duration,
+) {
+	t.Clock = t.Clock - timeTaken + lagForgiven + increment
+}
+
+/*
+status method    aggregates the members' statuses.
+A team is connected while any member is connected and
+only counts as never joined when no member has ever joined.
+*/
+func (t *team) status() Status {
+	status := INIT
+	for _, player := range t.players {
+		switch player.status() {
+		case CONNECTED:
+			return CONNECTED
+		case DISCONNECTED:
+			status = DISCONNECTED
+		}
+	}
+	return status
+}
+
+func (t *team) hasPlayer(id string) bool {
+	for _, player := range t.players {
+		if player.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// averageRating method    returns the average rating of the team members
+func (t *team) averageRating() float64 {
+	if len(t.players) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, player := range t.players {
+		sum += player.Rating
+	}
+	return sum / float64(len(t.players))
+}
+
+func parsePieceType(s string) chess.PieceType {
+	switch strings.ToLower(s) {
+	case "k", "king":
+		return chess.King
+	case "q", "queen":
+		return chess.Queen
+	case "r", "rook":
+		return chess.Rook
+	case "b", "bishop":
+		return chess.Bishop
+	case "n", "knight":
+		return chess.Knight
+	case "p", "pawn":
+		return chess.Pawn
+	default:
+		return chess.NoPieceType
+	}
+}
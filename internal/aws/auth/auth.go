@@ -6,16 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"math/big"
-	"net/http"
-	"os"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type jwk struct {
 	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
 	N   string `json:"n"`
 	E   string `json:"e"`
 }
@@ -24,36 +21,51 @@ type jwks struct {
 	Keys []jwk `json:"keys"`
 }
 
-// Load Cognito public keys
-func LoadCognitoPublicKeys(url string) (map[string]*rsa.PublicKey, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		fmt.Println("Error fetching Cognito public keys:", err)
-		return nil, fmt.Errorf("failed to fetch public keys: %w ", err)
-	}
-	defer resp.Body.Close()
+// CognitoIssuer function    returns the token issuer of a Cognito user pool
+func CognitoIssuer(region, userPoolId string) string {
+	return fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s", region, userPoolId)
+}
 
-	body, _ := io.ReadAll(resp.Body)
+// parseJwks function    decodes the RSA signing keys of a JWKS document by kid
+func parseJwks(data []byte) (map[string]*rsa.PublicKey, error) {
 	var jwks jwks
-	if err = json.Unmarshal(body, &jwks); err != nil {
+	if err := json.Unmarshal(data, &jwks); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal jwks: %w", err)
 	}
 
-	cognitoPublicKeys := make(map[string]*rsa.PublicKey)
+	publicKeys := make(map[string]*rsa.PublicKey)
 	for _, key := range jwks.Keys {
+		if (key.Kty != "" && key.Kty != "RSA") || key.Use == "enc" {
+			continue
+		}
+		if key.Kid == "" {
+			return nil, errors.New("invalid jwk: missing kid")
+		}
 		// Decode Base64URL (without padding) `n` and `e`
-		nBytes, _ := decodeBase64URL(key.N)
-		eBytes, _ := decodeBase64URL(key.E)
+		nBytes, err := decodeBase64URL(key.N)
+		if err != nil || len(nBytes) == 0 {
+			return nil, fmt.Errorf("invalid jwk %s: bad modulus: %w", key.Kid, err)
+		}
+		eBytes, err := decodeBase64URL(key.E)
+		if err != nil || len(eBytes) == 0 {
+			return nil, fmt.Errorf("invalid jwk %s: bad exponent: %w", key.Kid, err)
+		}
 
 		// Convert to big.Int and integer
 		n := new(big.Int).SetBytes(nBytes)
-		e := int(new(big.Int).SetBytes(eBytes).Int64())
+		e := new(big.Int).SetBytes(eBytes)
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid jwk %s: exponent out of range", key.Kid)
+		}
 
 		// Construct RSA Public Key
-		cognitoPublicKeys[key.Kid] = &rsa.PublicKey{N: n, E: e}
+		publicKeys[key.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	}
+	if len(publicKeys) == 0 {
+		return nil, errors.New("jwks has no rsa signing keys")
 	}
 
-	return cognitoPublicKeys, nil
+	return publicKeys, nil
 }
 
 // Decode Base64URL without padding
@@ -61,42 +73,6 @@ func decodeBase64URL(s string) ([]byte, error) {
 	return base64.RawURLEncoding.DecodeString(s)
 }
 
-// Validate JWT
-func ValidateJwt(
-	tokenString string,
-	cognitoPublicKeys map[string]*rsa.PublicKey,
-) (
-	*jwt.Token,
-	error,
-) {
-	if cognitoPublicKeys == nil {
-		return nil, fmt.Errorf("cognito public keys not loaded")
-	}
-	issuer := fmt.Sprintf(
-		"https://cognito-idp.%s.amazonaws.com/%s",
-		os.Getenv("AWS_REGION"),
-		os.Getenv("COGNITO_USER_POOL_ID"),
-	)
-	token, err := jwt.Parse(
-		tokenString,
-		func(token *jwt.Token) (interface{}, error) {
-			kid, ok := token.Header["kid"].(string)
-			if !ok {
-				return nil, errors.New("invalid token: missing kid")
-			}
-			if key, found := cognitoPublicKeys[kid]; found {
-				return key, nil
-			}
-			return nil, errors.New("invalid token: unknown kid")
-		},
-		jwt.WithIssuer(issuer),
-	)
-	if err != nil {
-		return nil, err
-	}
-	return token, nil
-}
-
 func MustAuth(authorizer map[string]interface{}) string {
 	jwt, ok := authorizer["jwt"].(map[string]interface{})
 	if !ok {
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	GuestTokenIssuer = "slchess-guest"
+	GuestTokenTTL    = 24 * time.Hour
+)
+
+var (
+	ErrGuestTokenSecretNotSet = errors.New("guest token secret not set")
+	ErrNoGuestToken           = errors.New("no guest token")
+)
+
+// GuestTokenClaims struct    identifies an anonymous guest session
+type GuestTokenClaims struct {
+	Guest bool `json:"guest"`
+	jwt.RegisteredClaims
+}
+
+// GuestTokenSecret function    returns the guest token signing secret of the lambdas
+func GuestTokenSecret() ([]byte, error) {
+	secret := os.Getenv("GUEST_TOKEN_SECRET")
+	if secret == "" {
+		return nil, ErrGuestTokenSecretNotSet
+	}
+	return []byte(secret), nil
+}
+
+// SignGuestToken function    issues a guest token for the given guest user id
+func SignGuestToken(guestId string, secret []byte) (string, time.Time, error) {
+	if len(secret) == 0 {
+		return "", time.Time{}, ErrGuestTokenSecretNotSet
+	}
+	now := time.Now()
+	expiresAt := now.Add(GuestTokenTTL)
+	claims := GuestTokenClaims{
+		Guest: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    GuestTokenIssuer,
+			Subject:   guestId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign guest token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// ParseGuestToken function    verifies a guest token and returns the guest user id
+func ParseGuestToken(token string, secret []byte) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrGuestTokenSecretNotSet
+	}
+	var claims GuestTokenClaims
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return secret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(GuestTokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("invalid guest token: %w", err)
+	}
+	if !claims.Guest || claims.Subject == "" {
+		return "", fmt.Errorf("invalid guest token: missing guest id")
+	}
+	return claims.Subject, nil
+}
+
+// GuestAuth function    returns the guest id of a request sent to a guest route, which has no Cognito authorizer
+func GuestAuth(headers map[string]string, secret []byte) (string, error) {
+	for name, value := range headers {
+		if !strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		if len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+			value = value[7:]
+		}
+		return ParseGuestToken(value, secret)
+	}
+	return "", ErrNoGuestToken
+}
+
+// Identify function    returns the caller of a route that accepts both Cognito users and guests
+func Identify(
+	authorizer map[string]interface{},
+	headers map[string]string,
+	guestSecret []byte,
+) (
+	string,
+	bool,
+	error,
+) {
+	if _, ok := authorizer["jwt"]; ok {
+		return MustAuth(authorizer), false, nil
+	}
+	guestId, err := GuestAuth(headers, guestSecret)
+	if err != nil {
+		return "", false, err
+	}
+	return guestId, true, nil
+}
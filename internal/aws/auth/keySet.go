@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultKeySetTTL = time.Hour
+	// An unknown kid refetches the keys at most this often, so forged kids can't flood the issuer
+	MinKeyRefreshInterval = 30 * time.Second
+
+	keyFetchTimeout = 10 * time.Second
+)
+
+var ErrUnknownKid = errors.New("unknown kid")
+
+// KeySource interface    loads the current signing keys of an issuer
+type KeySource interface {
+	Load() (map[string]*rsa.PublicKey, error)
+}
+
+// urlKeySource struct    fetches a JWKS document over HTTP
+type urlKeySource struct {
+	url    string
+	client *http.Client
+}
+
+// fileKeySource struct    reads a JWKS document from disk, for local and offline use
+type fileKeySource struct {
+	path string
+}
+
+func NewUrlKeySource(url string) KeySource {
+	return &urlKeySource{
+		url:    url,
+		client: &http.Client{Timeout: keyFetchTimeout},
+	}
+}
+
+func NewFileKeySource(path string) KeySource {
+	return &fileKeySource{path: path}
+}
+
+func (s *urlKeySource) Load() (map[string]*rsa.PublicKey, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch public keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch public keys: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public keys: %w", err)
+	}
+	return parseJwks(body)
+}
+
+func (s *fileKeySource) Load() (map[string]*rsa.PublicKey, error) {
+	body, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public keys: %w", err)
+	}
+	return parseJwks(body)
+}
+
+/*
+KeySet struct    caches the public keys of a key source.
+Keys are reloaded once they are older than the TTL, or when a token names a kid
+the cache has never seen, which is how a key rotation shows up.
+Reloads are spaced by MinKeyRefreshInterval whether they succeed or not.
+*/
+type KeySet struct {
+	source        KeySource
+	ttl           time.Duration
+	keys          map[string]*rsa.PublicKey
+	fetchedAt     time.Time
+	lastAttemptAt time.Time
+	mu            sync.Mutex
+}
+
+func NewKeySet(source KeySource, ttl time.Duration) *KeySet {
+	return &KeySet{
+		source: source,
+		ttl:    ttl,
+	}
+}
+
+// Key method    returns the public key with the given kid, refreshing the cache when needed
+func (k *KeySet) Key(kid string) (*rsa.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, found := k.keys[kid]
+	if found && time.Since(k.fetchedAt) < k.ttl {
+		return key, nil
+	}
+	if !k.lastAttemptAt.IsZero() && time.Since(k.lastAttemptAt) < MinKeyRefreshInterval {
+		if found {
+			return key, nil
+		}
+		return nil, ErrUnknownKid
+	}
+	k.lastAttemptAt = time.Now()
+	keys, err := k.source.Load()
+	if err != nil {
+		// Keep serving the cached keys while the source is unreachable
+		if found {
+			return key, nil
+		}
+		return nil, fmt.Errorf("failed to refresh keys: %w", err)
+	}
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	if key, found := k.keys[kid]; found {
+		return key, nil
+	}
+	return nil, ErrUnknownKid
+}
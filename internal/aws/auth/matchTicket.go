@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	MatchTicketIssuer   = "slchess"
+	MatchTicketAudience = "game-server"
+	MatchTicketTTL      = 5 * time.Minute
+)
+
+var ErrMatchTicketSecretNotSet = errors.New("match ticket secret not set")
+
+/*
+MatchTicketClaims struct    is everything the game server needs to start a match.
+Tickets issued for a restore only prove the match exists, the game server still reads
+the match state from DynamoDB for them.
+*/
+type MatchTicketClaims struct {
+	MatchId  string              `json:"matchId"`
+	GameMode string              `json:"gameMode"`
+	TeamMode string              `json:"teamMode,omitempty"`
+	SimulId  string              `json:"simulId,omitempty"`
+	Casual   bool                `json:"casual,omitempty"`
+	Players  []MatchTicketPlayer `json:"players"`
+	Restore  bool                `json:"restore,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type MatchTicketPlayer struct {
+	Id         string    `json:"id"`
+	Username   string    `json:"username"`
+	Rating     float64   `json:"rating"`
+	RD         float64   `json:"rd"`
+	NewRatings []float64 `json:"newRatings"`
+	NewRDs     []float64 `json:"newRDs"`
+	Team       int       `json:"team"`
+	Role       string    `json:"role,omitempty"`
+
+	Volatility      float64   `json:"volatility,omitempty"`
+	NewVolatilities []float64 `json:"newVolatilities,omitempty"`
+	Provisional     bool      `json:"provisional,omitempty"`
+}
+
+// MatchTicketSecret function    returns the shared match ticket signing secret of the lambdas
+func MatchTicketSecret() ([]byte, error) {
+	secret := os.Getenv("MATCH_TICKET_SECRET")
+	if secret == "" {
+		return nil, ErrMatchTicketSecretNotSet
+	}
+	return []byte(secret), nil
+}
+
+// SignMatchTicket function    issues a short-lived ticket for the match
+func SignMatchTicket(
+	activeMatch entities.ActiveMatch,
+	restore bool,
+	secret []byte,
+) (
+	string,
+	error,
+) {
+	if len(secret) == 0 {
+		return "", ErrMatchTicketSecretNotSet
+	}
+	now := time.Now()
+	claims := MatchTicketClaims{
+		MatchId:  activeMatch.MatchId,
+		GameMode: activeMatch.GameMode,
+		TeamMode: activeMatch.TeamMode,
+		SimulId:  activeMatch.SimulId,
+		Casual:   activeMatch.Casual,
+		Players:  make([]MatchTicketPlayer, 0, 2+len(activeMatch.Teammates)),
+		Restore:  restore,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    MatchTicketIssuer,
+			Audience:  jwt.ClaimStrings{MatchTicketAudience},
+			Subject:   activeMatch.MatchId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(MatchTicketTTL)),
+		},
+	}
+	for _, player := range activeMatch.Players() {
+		claims.Players = append(claims.Players, MatchTicketPlayer{
+			Id:              player.Id,
+			Username:        player.Username,
+			Rating:          player.Rating,
+			RD:              player.RD,
+			NewRatings:      player.NewRatings,
+			NewRDs:          player.NewRDs,
+			Team:            player.Team,
+			Role:            player.Role,
+			Volatility:      player.Volatility,
+			NewVolatilities: player.NewVolatilities,
+			Provisional:     player.Provisional,
+		})
+	}
+	ticket, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign match ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+// ParseMatchTicket function    verifies the ticket signature, audience and expiry
+func ParseMatchTicket(ticket string, secret []byte) (MatchTicketClaims, error) {
+	if len(secret) == 0 {
+		return MatchTicketClaims{}, ErrMatchTicketSecretNotSet
+	}
+	var claims MatchTicketClaims
+	_, err := jwt.ParseWithClaims(
+		ticket,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return secret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(MatchTicketIssuer),
+		jwt.WithAudience(MatchTicketAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return MatchTicketClaims{}, fmt.Errorf("invalid match ticket: %w", err)
+	}
+	if len(claims.Players) < 2 {
+		return MatchTicketClaims{}, fmt.Errorf("invalid match ticket: missing players")
+	}
+	return claims, nil
+}
+
+// ActiveMatch method    rebuilds the active match carried by the ticket
+func (c MatchTicketClaims) ActiveMatch() entities.ActiveMatch {
+	players := make([]entities.Player, 0, len(c.Players))
+	for _, player := range c.Players {
+		players = append(players, entities.Player{
+			Id:              player.Id,
+			Username:        player.Username,
+			Rating:          player.Rating,
+			RD:              player.RD,
+			NewRatings:      player.NewRatings,
+			NewRDs:          player.NewRDs,
+			Team:            player.Team,
+			Role:            player.Role,
+			Volatility:      player.Volatility,
+			NewVolatilities: player.NewVolatilities,
+			Provisional:     player.Provisional,
+		})
+	}
+	activeMatch := entities.ActiveMatch{
+		MatchId:  c.MatchId,
+		GameMode: c.GameMode,
+		TeamMode: c.TeamMode,
+		SimulId:  c.SimulId,
+		Casual:   c.Casual,
+		Player1:  players[0],
+		Player2:  players[1],
+	}
+	if len(players) > 2 {
+		activeMatch.Teammates = players[2:]
+	}
+	if c.IssuedAt != nil {
+		activeMatch.CreatedAt = c.IssuedAt.Time
+	}
+	return activeMatch
+}
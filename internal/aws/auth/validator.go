@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TOKEN_USE_ID     = "id"
+	TOKEN_USE_ACCESS = "access"
+)
+
+var (
+	ErrMissingKid      = errors.New("missing kid")
+	ErrInvalidAudience = errors.New("invalid audience")
+	ErrInvalidTokenUse = errors.New("invalid token_use")
+	ErrMissingSubject  = errors.New("missing subject")
+)
+
+/*
+ValidatorConfig struct    lists the checks a token must pass besides its signature.
+Audience is compared with aud, or with client_id for Cognito access tokens which carry no aud.
+An empty Audience or TokenUses skips that check.
+*/
+type ValidatorConfig struct {
+	Issuer    string
+	Audience  string
+	TokenUses []string
+	Leeway    time.Duration
+}
+
+// Claims struct    holds the claims read from a validated token
+type Claims struct {
+	TokenUse string `json:"token_use,omitempty"`
+	ClientId string `json:"client_id,omitempty"`
+	Username string `json:"cognito:username,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Validator struct    validates RS256 tokens against a key set
+type Validator struct {
+	cfg  ValidatorConfig
+	keys *KeySet
+}
+
+func NewValidator(keys *KeySet, cfg ValidatorConfig) *Validator {
+	return &Validator{
+		cfg:  cfg,
+		keys: keys,
+	}
+}
+
+/*
+NewCognitoValidator function    builds the validator of the lambdas from the environment.
+COGNITO_JWKS_FILE replaces the user pool JWKS endpoint with a local file,
+COGNITO_TOKEN_USE narrows the accepted token kinds to a comma separated list.
+*/
+func NewCognitoValidator() *Validator {
+	issuer := CognitoIssuer(os.Getenv("AWS_REGION"), os.Getenv("COGNITO_USER_POOL_ID"))
+	tokenUses := []string{TOKEN_USE_ID, TOKEN_USE_ACCESS}
+	if value := os.Getenv("COGNITO_TOKEN_USE"); value != "" {
+		tokenUses = strings.Split(value, ",")
+	}
+	var source KeySource
+	if path := os.Getenv("COGNITO_JWKS_FILE"); path != "" {
+		source = NewFileKeySource(path)
+	} else {
+		source = NewUrlKeySource(issuer + "/.well-known/jwks.json")
+	}
+	return NewValidator(
+		NewKeySet(source, DefaultKeySetTTL),
+		ValidatorConfig{
+			Issuer:    issuer,
+			Audience:  os.Getenv("COGNITO_USER_POOL_CLIENT_ID"),
+			TokenUses: tokenUses,
+		},
+	)
+}
+
+// Validate method    verifies the token signature, issuer, expiry, token_use and audience
+func (v *Validator) Validate(token string) (Claims, error) {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = token[7:]
+	}
+	var claims Claims
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, ok := token.Header["kid"].(string)
+			if !ok {
+				return nil, ErrMissingKid
+			}
+			return v.keys.Key(kid)
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(v.cfg.Leeway),
+	)
+	if err != nil {
+		return Claims{}, fmt.Errorf("invalid token: %w", err)
+	}
+	if len(v.cfg.TokenUses) > 0 && !slices.Contains(v.cfg.TokenUses, claims.TokenUse) {
+		return Claims{}, fmt.Errorf("invalid token: %w: %q", ErrInvalidTokenUse, claims.TokenUse)
+	}
+	if v.cfg.Audience != "" {
+		audience := []string(claims.Audience)
+		if claims.TokenUse == TOKEN_USE_ACCESS {
+			audience = []string{claims.ClientId}
+		}
+		if !slices.Contains(audience, v.cfg.Audience) {
+			return Claims{}, fmt.Errorf("invalid token: %w", ErrInvalidAudience)
+		}
+	}
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("invalid token: %w", ErrMissingSubject)
+	}
+	return claims, nil
+}
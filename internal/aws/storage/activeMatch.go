@@ -16,8 +16,12 @@ import (
 var ErrActiveMatchNotFound = fmt.Errorf("active match not found")
 
 type ActiveMatchUpdateOptions struct {
-	Server    *string
-	StartedAt *time.Time
+	Server      *string
+	StartedAt   *time.Time
+	Adjournment *entities.Adjournment
+
+	// ClearAdjournment removes the adjournment once the match is resumed
+	ClearAdjournment bool
 }
 
 func (client *Client) GetActiveMatch(
@@ -99,6 +103,30 @@ func (client *Client) FetchActiveMatches(
 	return activeMatches, output.LastEvaluatedKey, nil
 }
 
+// ScanAdjournedMatches method    returns a page of every adjourned active match, in no particular order
+func (client *Client) ScanAdjournedMatches(
+	ctx context.Context,
+	lastKey map[string]types.AttributeValue,
+) (
+	[]entities.ActiveMatch,
+	map[string]types.AttributeValue,
+	error,
+) {
+	output, err := client.dynamodb.Scan(ctx, &dynamodb.ScanInput{
+		TableName:         client.cfg.ActiveMatchesTableName,
+		FilterExpression:  aws.String("attribute_exists(Adjournment)"),
+		ExclusiveStartKey: lastKey,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var activeMatches []entities.ActiveMatch
+	if err := attributevalue.UnmarshalListOfMaps(output.Items, &activeMatches); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal active match maps: %w", err)
+	}
+	return activeMatches, output.LastEvaluatedKey, nil
+}
+
 func (client *Client) PutActiveMatch(
 	ctx context.Context,
 	activeMatch entities.ActiveMatch,
@@ -141,6 +169,26 @@ func (client *Client) UpdateActiveMatch(
 		}
 	}
 
+	if opts.Adjournment != nil {
+		av, err := attributevalue.Marshal(opts.Adjournment)
+		if err != nil {
+			return fmt.Errorf("failed to marshal adjournment: %w", err)
+		}
+		updateExpression = append(updateExpression, "Adjournment = :adjournment")
+		expressionAttributeValues[":adjournment"] = av
+	}
+
+	expression := ""
+	if len(updateExpression) > 0 {
+		expression = "SET " + strings.Join(updateExpression, ", ")
+	}
+	if opts.ClearAdjournment {
+		expression = strings.TrimSpace(expression + " REMOVE Adjournment")
+	}
+	if len(expressionAttributeValues) == 0 {
+		expressionAttributeValues = nil
+	}
+
 	_, err := client.dynamodb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: client.cfg.ActiveMatchesTableName,
 		Key: map[string]types.AttributeValue{
@@ -148,7 +196,7 @@ func (client *Client) UpdateActiveMatch(
 				Value: matchId,
 			},
 		},
-		UpdateExpression:          aws.String("SET " + strings.Join(updateExpression, ", ")),
+		UpdateExpression:          aws.String(expression),
 		ExpressionAttributeValues: expressionAttributeValues,
 	})
 	if err != nil {
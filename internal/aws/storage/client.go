@@ -30,6 +30,9 @@ type config struct {
 	FriendshipsTableName            *string
 	FriendRequestsTableName         *string
 	ApplicationEndpointsTableName   *string
+	SimulsTableName                 *string
+	UserBlocksTableName             *string
+	RatingHistoryTableName          *string
 }
 
 func NewClient(dynamoClient *dynamodb.Client) *Client {
@@ -92,5 +95,14 @@ func loadConfig() config {
 	if v, ok := os.LookupEnv("APPLICATION_ENDPOINTS_TABLE_NAME"); ok {
 		cfg.ApplicationEndpointsTableName = aws.String(v)
 	}
+	if v, ok := os.LookupEnv("SIMULS_TABLE_NAME"); ok {
+		cfg.SimulsTableName = aws.String(v)
+	}
+	if v, ok := os.LookupEnv("USER_BLOCKS_TABLE_NAME"); ok {
+		cfg.UserBlocksTableName = aws.String(v)
+	}
+	if v, ok := os.LookupEnv("RATING_HISTORY_TABLE_NAME"); ok {
+		cfg.RatingHistoryTableName = aws.String(v)
+	}
 	return cfg
 }
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+var ErrMatchConflict = fmt.Errorf("match conflict")
+
+// MatchConflictError struct    names the users whose ticket was gone or who were already in a match
+type MatchConflictError struct {
+	UserIds []string
+}
+
+func (e *MatchConflictError) Error() string {
+	return fmt.Sprintf("%s [userIds: %s]", ErrMatchConflict, strings.Join(e.UserIds, ","))
+}
+
+func (e *MatchConflictError) Unwrap() error {
+	return ErrMatchConflict
+}
+
+/*
+CreateMatch method    creates a matchmade match in a single transaction.
+Every ticket is taken off the queue only if it is still there, the user matches are created
+only if no user is already in a match, and the active match and its spectator conversation
+are written together with them. Nothing is written when any of the conditions fails,
+the returned *MatchConflictError then names the users that made it fail.
+*/
+func (client *Client) CreateMatch(
+	ctx context.Context,
+	match entities.ActiveMatch,
+	tickets []entities.MatchmakingTicket,
+	spectatorConversation entities.SpectatorConversation,
+) error {
+	var (
+		deletes    []types.TransactWriteItem
+		userPuts   []types.TransactWriteItem
+		matchPuts  []types.TransactWriteItem
+		conflicted []string
+	)
+	for _, ticket := range tickets {
+		deletes = append(deletes, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName: client.cfg.MatchmakingTicketsTableName,
+				Key: map[string]types.AttributeValue{
+					"UserId": &types.AttributeValueMemberS{Value: ticket.UserId},
+				},
+				ConditionExpression: aws.String("attribute_exists(UserId)"),
+			},
+		})
+
+		av, err := attributevalue.MarshalMap(entities.UserMatch{
+			UserId:  ticket.UserId,
+			MatchId: match.MatchId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal user match map: %w", err)
+		}
+		userPuts = append(userPuts, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           client.cfg.UserMatchesTableName,
+				Item:                av,
+				ConditionExpression: aws.String("attribute_not_exists(UserId)"),
+			},
+		})
+	}
+
+	matchAv, err := attributevalue.MarshalMap(match)
+	if err != nil {
+		return fmt.Errorf("failed to marshal active match map: %w", err)
+	}
+	conversationAv, err := attributevalue.MarshalMap(spectatorConversation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spectator conversation map: %w", err)
+	}
+	matchPuts = append(matchPuts,
+		types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           client.cfg.ActiveMatchesTableName,
+				Item:                matchAv,
+				ConditionExpression: aws.String("attribute_not_exists(MatchId)"),
+			},
+		},
+		types.TransactWriteItem{
+			Put: &types.Put{
+				TableName: client.cfg.SpectatorConversationsTableName,
+				Item:      conversationAv,
+			},
+		},
+	)
+
+	// Deletes and user matches come first so a cancellation reason maps back to its ticket by index
+	items := append(append(deletes, userPuts...), matchPuts...)
+	_, err = client.dynamodb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if !errors.As(err, &canceled) {
+			return err
+		}
+		for i, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) != "ConditionalCheckFailed" ||
+				i >= len(deletes)+len(userPuts) {
+				continue
+			}
+			userId := tickets[i%len(tickets)].UserId
+			if !slices.Contains(conflicted, userId) {
+				conflicted = append(conflicted, userId)
+			}
+		}
+		if len(conflicted) == 0 && !hasConditionFailure(canceled) {
+			return err
+		}
+		return &MatchConflictError{UserIds: conflicted}
+	}
+
+	return nil
+}
+
+func hasConditionFailure(canceled *types.TransactionCanceledException) bool {
+	for _, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
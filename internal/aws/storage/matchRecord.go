@@ -40,6 +40,29 @@ func (client *Client) GetMatchRecord(
 	return matchRecord, nil
 }
 
+// ScanMatchRecords method    returns a page of every match record, in no particular order
+func (client *Client) ScanMatchRecords(
+	ctx context.Context,
+	lastKey map[string]types.AttributeValue,
+) (
+	[]entities.MatchRecord,
+	map[string]types.AttributeValue,
+	error,
+) {
+	output, err := client.dynamodb.Scan(ctx, &dynamodb.ScanInput{
+		TableName:         client.cfg.MatchRecordsTableName,
+		ExclusiveStartKey: lastKey,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var matchRecords []entities.MatchRecord
+	if err := attributevalue.UnmarshalListOfMaps(output.Items, &matchRecords); err != nil {
+		return nil, nil, err
+	}
+	return matchRecords, output.LastEvaluatedKey, nil
+}
+
 func (client *Client) PutMatchRecord(
 	ctx context.Context,
 	matchRecord entities.MatchRecord,
@@ -59,3 +59,25 @@ func (client *Client) PutMatchResult(
 	}
 	return nil
 }
+
+func (client *Client) DeleteMatchResult(
+	ctx context.Context,
+	userId string,
+	timestamp string,
+) error {
+	_, err := client.dynamodb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: client.cfg.MatchResultsTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberS{
+				Value: userId,
+			},
+			"Timestamp": &types.AttributeValueMemberS{
+				Value: timestamp,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -11,43 +14,51 @@ import (
 	"github.com/chess-vn/slchess/internal/domains/entities"
 )
 
-func (client *Client) ScanMatchmakingTickets(
+var ErrMatchmakingTicketNotFound = fmt.Errorf("matchmaking ticket not found")
+
+// FetchMatchmakingTickets method    returns every open ticket of a game mode and pool
+func (client *Client) FetchMatchmakingTickets(
 	ctx context.Context,
-	ticket entities.MatchmakingTicket,
+	gameMode string,
+	pool string,
 ) (
 	[]entities.MatchmakingTicket,
 	error,
 ) {
-	filter := "UserRating >= :min AND UserRating <= :max AND MinRating <= :rating AND MaxRating >= :rating AND GameMode = :mode"
-	output, err := client.dynamodb.Scan(ctx, &dynamodb.ScanInput{
-		TableName:        client.cfg.MatchmakingTicketsTableName,
-		FilterExpression: aws.String(filter),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":min": &types.AttributeValueMemberN{
-				Value: strconv.Itoa(int(ticket.MinRating)),
-			},
-			":max": &types.AttributeValueMemberN{
-				Value: strconv.Itoa(int(ticket.MaxRating)),
-			},
-			":rating": &types.AttributeValueMemberN{
-				Value: strconv.Itoa(int(ticket.UserRating)),
+	var (
+		tickets []entities.MatchmakingTicket
+		lastKey map[string]types.AttributeValue
+	)
+	for {
+		output, err := client.dynamodb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              client.cfg.MatchmakingTicketsTableName,
+			IndexName:              aws.String("GameModeIndex"),
+			KeyConditionExpression: aws.String("GameMode = :mode"),
+			FilterExpression:       aws.String("Pool = :pool"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":mode": &types.AttributeValueMemberS{
+					Value: gameMode,
+				},
+				":pool": &types.AttributeValueMemberS{
+					Value: pool,
+				},
 			},
-			":mode": &types.AttributeValueMemberS{
-				Value: ticket.GameMode,
-			},
-		},
-	})
-	if err != nil {
-		return nil, err
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var page []entities.MatchmakingTicket
+		err = attributevalue.UnmarshalListOfMaps(output.Items, &page)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, page...)
+		if output.LastEvaluatedKey == nil {
+			return tickets, nil
+		}
+		lastKey = output.LastEvaluatedKey
 	}
-
-	var tickets []entities.MatchmakingTicket
-	err = attributevalue.UnmarshalListOfMaps(output.Items, &tickets)
-	if err != nil {
-		return nil, err
-	}
-
-	return tickets, nil
 }
 
 func (client *Client) PutMatchmakingTickets(
@@ -80,3 +91,48 @@ func (client *Client) DeleteMatchmakingTicket(
 	}
 	return nil
 }
+
+// RefreshMatchmakingTicket method    records a heartbeat on a queued ticket and returns the refreshed ticket
+func (client *Client) RefreshMatchmakingTicket(
+	ctx context.Context,
+	userId string,
+	now time.Time,
+) (
+	entities.MatchmakingTicket,
+	error,
+) {
+	ticket := entities.MatchmakingTicket{}
+	ticket.Refresh(now)
+	output, err := client.dynamodb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: client.cfg.MatchmakingTicketsTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberS{Value: userId},
+		},
+		UpdateExpression:    aws.String("SET HeartbeatAt = :heartbeatAt, #ttl = :ttl"),
+		ConditionExpression: aws.String("attribute_exists(UserId)"),
+		ExpressionAttributeNames: map[string]string{
+			"#ttl": "TTL",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":heartbeatAt": &types.AttributeValueMemberS{
+				Value: ticket.HeartbeatAt.Format(time.RFC3339Nano),
+			},
+			":ttl": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(ticket.TTL, 10),
+			},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var condCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckFailed) {
+			return entities.MatchmakingTicket{}, ErrMatchmakingTicketNotFound
+		}
+		return entities.MatchmakingTicket{}, err
+	}
+	if err := attributevalue.UnmarshalMap(output.Attributes, &ticket); err != nil {
+		return entities.MatchmakingTicket{},
+			fmt.Errorf("failed to unmarshal matchmaking ticket map: %w", err)
+	}
+	return ticket, nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+// FetchRatingHistory method    returns the entries of a user's category rated between from and to, oldest first
+func (client *Client) FetchRatingHistory(
+	ctx context.Context,
+	userId string,
+	category string,
+	from time.Time,
+	to time.Time,
+) (
+	[]entities.RatingHistoryEntry,
+	error,
+) {
+	var (
+		entries []entities.RatingHistoryEntry
+		lastKey map[string]types.AttributeValue
+	)
+	for {
+		output, err := client.dynamodb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              client.cfg.RatingHistoryTableName,
+			KeyConditionExpression: aws.String("UserId = :userId AND SortKey BETWEEN :from AND :to"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":userId": &types.AttributeValueMemberS{
+					Value: userId,
+				},
+				":from": &types.AttributeValueMemberS{
+					Value: entities.RatingHistorySortKey(category, from),
+				},
+				// Match ids follow the timestamp, "~" sorts after all of them
+				":to": &types.AttributeValueMemberS{
+					Value: entities.RatingHistorySortKey(category, to) + "~",
+				},
+			},
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var page []entities.RatingHistoryEntry
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &page); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if output.LastEvaluatedKey == nil {
+			return entries, nil
+		}
+		lastKey = output.LastEvaluatedKey
+	}
+}
+
+func (client *Client) PutRatingHistoryEntry(
+	ctx context.Context,
+	entry entities.RatingHistoryEntry,
+) error {
+	av, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rating history entry map: %w", err)
+	}
+	_, err = client.dynamodb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: client.cfg.RatingHistoryTableName,
+		Item:      av,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (client *Client) DeleteRatingHistoryEntry(
+	ctx context.Context,
+	userId string,
+	sortKey string,
+) error {
+	_, err := client.dynamodb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: client.cfg.RatingHistoryTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberS{
+				Value: userId,
+			},
+			"SortKey": &types.AttributeValueMemberS{
+				Value: sortKey,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
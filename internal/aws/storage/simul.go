@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+var (
+	ErrSimulNotFound = fmt.Errorf("simul not found")
+	ErrSimulClosed   = fmt.Errorf("simul is full or no longer open")
+)
+
+type SimulUpdateOptions struct {
+	Status    *string
+	Server    *string
+	MatchIds  []string
+	StartedAt *time.Time
+}
+
+func (client *Client) GetSimul(
+	ctx context.Context,
+	simulId string,
+) (
+	entities.Simul,
+	error,
+) {
+	output, err := client.dynamodb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: client.cfg.SimulsTableName,
+		Key: map[string]types.AttributeValue{
+			"SimulId": &types.AttributeValueMemberS{
+				Value: simulId,
+			},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return entities.Simul{}, err
+	}
+	if output.Item == nil {
+		return entities.Simul{}, ErrSimulNotFound
+	}
+	var simul entities.Simul
+	if err := attributevalue.UnmarshalMap(output.Item, &simul); err != nil {
+		return entities.Simul{}, err
+	}
+	return simul, nil
+}
+
+func (client *Client) PutSimul(
+	ctx context.Context,
+	simul entities.Simul,
+) error {
+	av, err := attributevalue.MarshalMap(simul)
+	if err != nil {
+		return fmt.Errorf("failed to marshal simul map: %w", err)
+	}
+	_, err = client.dynamodb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: client.cfg.SimulsTableName,
+		Item:      av,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddSimulParticipant method    signs a user up for an open simul that still has free boards
+func (client *Client) AddSimulParticipant(
+	ctx context.Context,
+	simulId string,
+	userId string,
+) error {
+	_, err := client.dynamodb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: client.cfg.SimulsTableName,
+		Key: map[string]types.AttributeValue{
+			"SimulId": &types.AttributeValueMemberS{
+				Value: simulId,
+			},
+		},
+		UpdateExpression:    aws.String("SET Participants = list_append(Participants, :userIds)"),
+		ConditionExpression: aws.String("#status = :open AND size(Participants) < MaxParticipants AND NOT contains(Participants, :userId) AND HostId <> :userId"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":userIds": &types.AttributeValueMemberL{
+				Value: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: userId},
+				},
+			},
+			":userId": &types.AttributeValueMemberS{Value: userId},
+			":open":   &types.AttributeValueMemberS{Value: entities.SimulStatusOpen},
+		},
+	})
+	if err != nil {
+		var condCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &condCheckFailed) {
+			return ErrSimulClosed
+		}
+		return err
+	}
+	return nil
+}
+
+func (client *Client) UpdateSimul(
+	ctx context.Context,
+	simulId string,
+	opts SimulUpdateOptions,
+) error {
+	updateExpression := []string{}
+	expressionAttributeNames := map[string]string{}
+	expressionAttributeValues := map[string]types.AttributeValue{}
+
+	if opts.Status != nil {
+		updateExpression = append(updateExpression, "#status = :status")
+		expressionAttributeNames["#status"] = "Status"
+		expressionAttributeValues[":status"] = &types.AttributeValueMemberS{
+			Value: *opts.Status,
+		}
+	}
+
+	if opts.Server != nil {
+		updateExpression = append(updateExpression, "Server = :server")
+		expressionAttributeValues[":server"] = &types.AttributeValueMemberS{
+			Value: *opts.Server,
+		}
+	}
+
+	if opts.MatchIds != nil {
+		matchIds := make([]types.AttributeValue, 0, len(opts.MatchIds))
+		for _, matchId := range opts.MatchIds {
+			matchIds = append(matchIds, &types.AttributeValueMemberS{Value: matchId})
+		}
+		updateExpression = append(updateExpression, "MatchIds = :matchIds")
+		expressionAttributeValues[":matchIds"] = &types.AttributeValueMemberL{
+			Value: matchIds,
+		}
+	}
+
+	if opts.StartedAt != nil {
+		updateExpression = append(updateExpression, "StartedAt = :startedAt")
+		expressionAttributeValues[":startedAt"] = &types.AttributeValueMemberS{
+			Value: opts.StartedAt.Format(time.RFC3339),
+		}
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: client.cfg.SimulsTableName,
+		Key: map[string]types.AttributeValue{
+			"SimulId": &types.AttributeValueMemberS{
+				Value: simulId,
+			},
+		},
+		UpdateExpression:          aws.String("SET " + strings.Join(updateExpression, ", ")),
+		ExpressionAttributeValues: expressionAttributeValues,
+	}
+	if len(expressionAttributeNames) > 0 {
+		input.ExpressionAttributeNames = expressionAttributeNames
+	}
+	_, err := client.dynamodb.UpdateItem(ctx, input)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+func (client *Client) PutUserBlock(ctx context.Context, userBlock entities.UserBlock) error {
+	av, err := attributevalue.MarshalMap(userBlock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user block map: %w", err)
+	}
+	_, err = client.dynamodb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: client.cfg.UserBlocksTableName,
+		Item:      av,
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (client *Client) DeleteUserBlock(ctx context.Context, userId, blockedId string) error {
+	_, err := client.dynamodb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: client.cfg.UserBlocksTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId":    &types.AttributeValueMemberS{Value: userId},
+			"BlockedId": &types.AttributeValueMemberS{Value: blockedId},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FetchBlockedIds method    returns every user the given user blocked
+func (client *Client) FetchBlockedIds(ctx context.Context, userId string) ([]string, error) {
+	var (
+		blockedIds []string
+		lastKey    map[string]types.AttributeValue
+	)
+	for {
+		output, err := client.dynamodb.Query(ctx, &dynamodb.QueryInput{
+			TableName:              client.cfg.UserBlocksTableName,
+			KeyConditionExpression: aws.String("UserId = :userId"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":userId": &types.AttributeValueMemberS{Value: userId},
+			},
+			ExclusiveStartKey: lastKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var userBlocks []entities.UserBlock
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &userBlocks); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal user block maps: %w", err)
+		}
+		for _, userBlock := range userBlocks {
+			blockedIds = append(blockedIds, userBlock.BlockedId)
+		}
+		if output.LastEvaluatedKey == nil {
+			return blockedIds, nil
+		}
+		lastKey = output.LastEvaluatedKey
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -106,3 +107,53 @@ func (client *Client) UpdateUserProfile(
 
 	return nil
 }
+
+// IncrementMissedFirstMoves method    records that the user let a game abort by not making a first move
+func (client *Client) IncrementMissedFirstMoves(
+	ctx context.Context,
+	userId string,
+	missedAt time.Time,
+) error {
+	_, err := client.dynamodb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: client.cfg.UserProfilesTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberS{
+				Value: userId,
+			},
+		},
+		UpdateExpression: aws.String(
+			"ADD MissedFirstMoves :one SET LastMissedFirstMoveAt = :missedAt",
+		),
+		ConditionExpression: aws.String("attribute_exists(UserId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{
+				Value: "1",
+			},
+			":missedAt": &types.AttributeValueMemberS{
+				Value: missedAt.Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (client *Client) DeleteUserProfile(
+	ctx context.Context,
+	userId string,
+) error {
+	_, err := client.dynamodb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: client.cfg.UserProfilesTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberS{
+				Value: userId,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
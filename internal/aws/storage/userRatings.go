@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -18,6 +19,7 @@ var ErrUserRatingNotFound = fmt.Errorf("user rating not found")
 func (client *Client) GetUserRating(
 	ctx context.Context,
 	userId string,
+	category string,
 ) (
 	entities.UserRating,
 	error,
@@ -28,11 +30,17 @@ func (client *Client) GetUserRating(
 			"UserId": &types.AttributeValueMemberS{
 				Value: userId,
 			},
+			"Category": &types.AttributeValueMemberS{
+				Value: category,
+			},
 		},
 	})
 	if err != nil {
 		return entities.UserRating{}, err
 	}
+	if output.Item == nil {
+		return entities.UserRating{}, ErrUserRatingNotFound
+	}
 	var userRating entities.UserRating
 	if err := attributevalue.UnmarshalMap(output.Item, &userRating); err != nil {
 		return entities.UserRating{}, err
@@ -40,8 +48,40 @@ func (client *Client) GetUserRating(
 	return userRating, nil
 }
 
+// GetUserRatings method    returns the ratings of a user in every category
+func (client *Client) GetUserRatings(
+	ctx context.Context,
+	userId string,
+) (
+	[]entities.UserRating,
+	error,
+) {
+	output, err := client.dynamodb.Query(ctx, &dynamodb.QueryInput{
+		TableName:              client.cfg.UserRatingsTableName,
+		KeyConditionExpression: aws.String("UserId = :userId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":userId": &types.AttributeValueMemberS{
+				Value: userId,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.Items) == 0 {
+		return nil, ErrUserRatingNotFound
+	}
+	var userRatings []entities.UserRating
+	if err := attributevalue.UnmarshalListOfMaps(output.Items, &userRatings); err != nil {
+		return nil, err
+	}
+	return userRatings, nil
+}
+
+// FetchUserRatings method    returns the leaderboard of a category, highest rating first
 func (client *Client) FetchUserRatings(
 	ctx context.Context,
+	category string,
 	lastKey map[string]types.AttributeValue,
 	limit int32,
 ) (
@@ -58,7 +98,7 @@ func (client *Client) FetchUserRatings(
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":pk": &types.AttributeValueMemberS{
-				Value: "UserRatings",
+				Value: entities.RatingsPartitionKey(category, false),
 			},
 		},
 		ExclusiveStartKey: lastKey,
@@ -97,13 +137,16 @@ func (client *Client) PutUserRating(
 }
 
 type UserRatingUpdateOptions struct {
-	Rating *float64
-	RD     *float64
+	Rating     *float64
+	RD         *float64
+	Volatility *float64
+	RatedAt    *time.Time
 }
 
 func (client *Client) UpdateUserRating(
 	ctx context.Context,
 	userId string,
+	category string,
 	opts UserRatingUpdateOptions,
 ) error {
 	updateExpression := []string{}
@@ -123,12 +166,29 @@ func (client *Client) UpdateUserRating(
 		}
 	}
 
+	if opts.Volatility != nil {
+		updateExpression = append(updateExpression, "Volatility = :volatility")
+		expressionAttributeValues[":volatility"] = &types.AttributeValueMemberN{
+			Value: strconv.FormatFloat(*opts.Volatility, 'f', 6, 64),
+		}
+	}
+
+	if opts.RatedAt != nil {
+		updateExpression = append(updateExpression, "RatedAt = :ratedAt")
+		expressionAttributeValues[":ratedAt"] = &types.AttributeValueMemberS{
+			Value: opts.RatedAt.Format(time.RFC3339),
+		}
+	}
+
 	_, err := client.dynamodb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: client.cfg.UserRatingsTableName,
 		Key: map[string]types.AttributeValue{
 			"UserId": &types.AttributeValueMemberS{
 				Value: userId,
 			},
+			"Category": &types.AttributeValueMemberS{
+				Value: category,
+			},
 		},
 		UpdateExpression:          aws.String("SET " + strings.Join(updateExpression, ", ")),
 		ExpressionAttributeValues: expressionAttributeValues,
@@ -139,3 +199,25 @@ func (client *Client) UpdateUserRating(
 
 	return nil
 }
+
+func (client *Client) DeleteUserRating(
+	ctx context.Context,
+	userId string,
+	category string,
+) error {
+	_, err := client.dynamodb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: client.cfg.UserRatingsTableName,
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberS{
+				Value: userId,
+			},
+			"Category": &types.AttributeValueMemberS{
+				Value: category,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -7,14 +7,20 @@ import (
 )
 
 type ActiveMatchResponse struct {
-	MatchId        string         `json:"matchId"`
-	ConversationId string         `json:"conversationId,omitempty"`
-	Player1        PlayerResponse `json:"player1"`
-	Player2        PlayerResponse `json:"player2"`
-	GameMode       string         `json:"gameMode"`
-	Server         string         `json:"server,omitempty"`
-	StartedAt      *time.Time     `json:"startedAt"`
-	CreatedAt      time.Time      `json:"createdAt"`
+	MatchId         string           `json:"matchId"`
+	ConversationId  string           `json:"conversationId,omitempty"`
+	Player1         PlayerResponse   `json:"player1"`
+	Player2         PlayerResponse   `json:"player2"`
+	Teammates       []PlayerResponse `json:"teammates,omitempty"`
+	TeamMode        string           `json:"teamMode,omitempty"`
+	GameMode        string           `json:"gameMode"`
+	Casual          bool             `json:"casual,omitempty"`
+	ColorAllocation string           `json:"colorAllocation,omitempty"`
+	Server          string           `json:"server,omitempty"`
+	Ticket          string           `json:"ticket,omitempty"`
+	AdjournedUntil  *time.Time       `json:"adjournedUntil,omitempty"`
+	StartedAt       *time.Time       `json:"startedAt"`
+	CreatedAt       time.Time        `json:"createdAt"`
 }
 
 type PlayerResponse struct {
@@ -22,6 +28,8 @@ type PlayerResponse struct {
 	Username   string    `json:"username"`
 	Rating     float64   `json:"rating"`
 	NewRatings []float64 `json:"newRatings,omitempty"`
+	Team       int       `json:"team"`
+	Role       string    `json:"role,omitempty"`
 }
 
 type ActiveMatchListResponse struct {
@@ -34,7 +42,7 @@ type NextActiveMatchPageToken struct {
 }
 
 func ActiveMatchResponseFromEntity(activeMatch entities.ActiveMatch) ActiveMatchResponse {
-	return ActiveMatchResponse{
+	resp := ActiveMatchResponse{
 		MatchId:        activeMatch.MatchId,
 		ConversationId: activeMatch.ConversationId,
 		Player1: PlayerResponse{
@@ -42,18 +50,38 @@ func ActiveMatchResponseFromEntity(activeMatch entities.ActiveMatch) ActiveMatch
 			Username:   activeMatch.Player1.Username,
 			Rating:     activeMatch.Player1.Rating,
 			NewRatings: activeMatch.Player1.NewRatings,
+			Role:       activeMatch.Player1.Role,
 		},
 		Player2: PlayerResponse{
 			Id:         activeMatch.Player2.Id,
 			Username:   activeMatch.Player2.Username,
 			Rating:     activeMatch.Player2.Rating,
 			NewRatings: activeMatch.Player2.NewRatings,
+			Team:       1,
+			Role:       activeMatch.Player2.Role,
 		},
-		GameMode:  activeMatch.GameMode,
-		Server:    activeMatch.Server,
-		StartedAt: activeMatch.StartedAt,
-		CreatedAt: activeMatch.CreatedAt,
+		TeamMode:        activeMatch.TeamMode,
+		GameMode:        activeMatch.GameMode,
+		Casual:          activeMatch.Casual,
+		ColorAllocation: activeMatch.ColorAllocation,
+		Server:          activeMatch.Server,
+		StartedAt:       activeMatch.StartedAt,
+		CreatedAt:       activeMatch.CreatedAt,
 	}
+	if activeMatch.Adjournment != nil {
+		resp.AdjournedUntil = &activeMatch.Adjournment.Deadline
+	}
+	for _, teammate := range activeMatch.Teammates {
+		resp.Teammates = append(resp.Teammates, PlayerResponse{
+			Id:         teammate.Id,
+			Username:   teammate.Username,
+			Rating:     teammate.Rating,
+			NewRatings: teammate.NewRatings,
+			Team:       teammate.Team,
+			Role:       teammate.Role,
+		})
+	}
+	return resp
 }
 
 func ActiveMatchListResponseFromEntities(activeMatches []entities.ActiveMatch) ActiveMatchListResponse {
@@ -3,4 +3,7 @@ package dtos
 type MatchAbortRequest struct {
 	MatchId   string   `json:"matchId"`
 	PlayerIds []string `json:"playerIds"`
+
+	// Players whose side never made its first move, the match is a no-contest
+	NoShowPlayerIds []string `json:"noShowPlayerIds,omitempty"`
 }
@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+type GuestSessionResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+	User      UserResponse `json:"user"`
+}
+
+type GuestConvertRequest struct {
+	GuestToken string `json:"guestToken"`
+}
+
+type GuestConvertResponse struct {
+	MergedMatches int          `json:"mergedMatches"`
+	RatingMerged  bool         `json:"ratingMerged"`
+	User          UserResponse `json:"user"`
+}
@@ -7,12 +7,35 @@ import (
 )
 
 type MatchRecordRequest struct {
-	MatchId   string                `json:"matchId"`
-	Players   []PlayerRecordRequest `json:"players"`
-	Pgn       string                `json:"pgn"`
-	StartedAt time.Time             `json:"startedAt"`
-	EndedAt   time.Time             `json:"endedAt"`
-	Results   []float64             `json:"results"`
+	MatchId   string                     `json:"matchId"`
+	GameMode  string                     `json:"gameMode"`
+	Players   []PlayerRecordRequest      `json:"players"`
+	Pgn       string                     `json:"pgn"`
+	Plies     []PlyRecordRequest         `json:"plies"`
+	Chat      []ChatMessageRecordRequest `json:"chat"`
+	StartedAt time.Time                  `json:"startedAt"`
+	EndedAt   time.Time                  `json:"endedAt"`
+	Results   []float64                  `json:"results"`
+	Casual    bool                       `json:"casual,omitempty"`
+}
+
+type PlyRecordRequest struct {
+	Ply         int       `json:"ply"`
+	PlayerId    string    `json:"playerId"`
+	Uci         string    `json:"uci"`
+	Clock       string    `json:"clock"`
+	TimeTaken   string    `json:"timeTaken"`
+	LagForgiven string    `json:"lagForgiven"`
+	Increment   string    `json:"increment"`
+	ReceivedAt  time.Time `json:"receivedAt"`
+}
+
+type ChatMessageRecordRequest struct {
+	PlayerId  string    `json:"playerId"`
+	Username  string    `json:"username"`
+	Content   string    `json:"content"`
+	Quick     string    `json:"quick,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type PlayerRecordRequest struct {
@@ -21,59 +44,138 @@ type PlayerRecordRequest struct {
 	NewRating float64 `json:"newRating"`
 	OldRD     float64 `json:"oldRD"`
 	NewRD     float64 `json:"newRD"`
+	Team      int     `json:"team"`
+	// Missing when the server could not tell, endGame then keeps the initial volatility
+	NewVolatility float64 `json:"newVolatility,omitempty"`
+	// Set when the game leaves the player's rating as it was
+	Unrated bool `json:"unrated,omitempty"`
 }
 
 type PlayerRecordGetResponse struct {
 	Id        string  `json:"id"`
 	OldRating float64 `json:"oldRating"`
 	NewRating float64 `json:"newRating"`
+	Team      int     `json:"team"`
+}
+
+type PlyRecordGetResponse struct {
+	Ply         int       `json:"ply"`
+	PlayerId    string    `json:"playerId"`
+	Uci         string    `json:"uci"`
+	Clock       string    `json:"clock"`
+	TimeTaken   string    `json:"timeTaken"`
+	LagForgiven string    `json:"lagForgiven"`
+	Increment   string    `json:"increment"`
+	ReceivedAt  time.Time `json:"receivedAt"`
+}
+
+type ChatMessageRecordGetResponse struct {
+	PlayerId  string    `json:"playerId"`
+	Username  string    `json:"username"`
+	Content   string    `json:"content"`
+	Quick     string    `json:"quick,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type MatchRecordGetResponse struct {
-	MatchId   string                    `json:"matchId"`
-	Players   []PlayerRecordGetResponse `json:"players"`
-	Pgn       string                    `json:"pgn"`
-	StartedAt time.Time                 `json:"startedAt"`
-	EndedAt   time.Time                 `json:"endedAt"`
+	MatchId   string                         `json:"matchId"`
+	Players   []PlayerRecordGetResponse      `json:"players"`
+	Pgn       string                         `json:"pgn"`
+	Plies     []PlyRecordGetResponse         `json:"plies"`
+	Chat      []ChatMessageRecordGetResponse `json:"chat"`
+	StartedAt time.Time                      `json:"startedAt"`
+	EndedAt   time.Time                      `json:"endedAt"`
 }
 
 func MatchRecordRequestToEntity(req MatchRecordRequest) entities.MatchRecord {
+	players := make([]entities.PlayerRecord, 0, len(req.Players))
+	for _, player := range req.Players {
+		players = append(players, entities.PlayerRecord{
+			Id:        player.Id,
+			OldRating: player.OldRating,
+			NewRating: player.NewRating,
+			OldRD:     player.OldRD,
+			NewRD:     player.NewRD,
+			Team:      player.Team,
+			Unrated:   player.Unrated,
+		})
+	}
+	plies := make([]entities.PlyRecord, 0, len(req.Plies))
+	for _, ply := range req.Plies {
+		plies = append(plies, entities.PlyRecord{
+			Ply:         ply.Ply,
+			PlayerId:    ply.PlayerId,
+			Uci:         ply.Uci,
+			Clock:       ply.Clock,
+			TimeTaken:   ply.TimeTaken,
+			LagForgiven: ply.LagForgiven,
+			Increment:   ply.Increment,
+			ReceivedAt:  ply.ReceivedAt,
+		})
+	}
+	chat := make([]entities.ChatMessageRecord, 0, len(req.Chat))
+	for _, msg := range req.Chat {
+		chat = append(chat, entities.ChatMessageRecord{
+			PlayerId:  msg.PlayerId,
+			Username:  msg.Username,
+			Content:   msg.Content,
+			Quick:     msg.Quick,
+			CreatedAt: msg.CreatedAt,
+		})
+	}
 	return entities.MatchRecord{
-		MatchId: req.MatchId,
-		Players: []entities.PlayerRecord{
-			{
-				Id:        req.Players[0].Id,
-				OldRating: req.Players[0].OldRating,
-				NewRating: req.Players[0].NewRating,
-			},
-			{
-				Id:        req.Players[1].Id,
-				OldRating: req.Players[1].OldRating,
-				NewRating: req.Players[1].NewRating,
-			},
-		},
+		MatchId:   req.MatchId,
+		GameMode:  req.GameMode,
+		Players:   players,
 		Pgn:       req.Pgn,
+		Plies:     plies,
+		Chat:      chat,
 		StartedAt: req.StartedAt,
 		EndedAt:   req.EndedAt,
+		Results:   req.Results,
+		Casual:    req.Casual,
 	}
 }
 
 func MatchRecordGetResponseFromEntity(matchRecord entities.MatchRecord) MatchRecordGetResponse {
+	players := make([]PlayerRecordGetResponse, 0, len(matchRecord.Players))
+	for _, player := range matchRecord.Players {
+		players = append(players, PlayerRecordGetResponse{
+			Id:        player.Id,
+			OldRating: player.OldRating,
+			NewRating: player.NewRating,
+			Team:      player.Team,
+		})
+	}
+	plies := make([]PlyRecordGetResponse, 0, len(matchRecord.Plies))
+	for _, ply := range matchRecord.Plies {
+		plies = append(plies, PlyRecordGetResponse{
+			Ply:         ply.Ply,
+			PlayerId:    ply.PlayerId,
+			Uci:         ply.Uci,
+			Clock:       ply.Clock,
+			TimeTaken:   ply.TimeTaken,
+			LagForgiven: ply.LagForgiven,
+			Increment:   ply.Increment,
+			ReceivedAt:  ply.ReceivedAt,
+		})
+	}
+	chat := make([]ChatMessageRecordGetResponse, 0, len(matchRecord.Chat))
+	for _, msg := range matchRecord.Chat {
+		chat = append(chat, ChatMessageRecordGetResponse{
+			PlayerId:  msg.PlayerId,
+			Username:  msg.Username,
+			Content:   msg.Content,
+			Quick:     msg.Quick,
+			CreatedAt: msg.CreatedAt,
+		})
+	}
 	return MatchRecordGetResponse{
-		MatchId: matchRecord.MatchId,
-		Players: []PlayerRecordGetResponse{
-			{
-				Id:        matchRecord.Players[0].Id,
-				OldRating: matchRecord.Players[0].OldRating,
-				NewRating: matchRecord.Players[0].NewRating,
-			},
-			{
-				Id:        matchRecord.Players[1].Id,
-				OldRating: matchRecord.Players[1].OldRating,
-				NewRating: matchRecord.Players[1].NewRating,
-			},
-		},
+		MatchId:   matchRecord.MatchId,
+		Players:   players,
 		Pgn:       matchRecord.Pgn,
+		Plies:     plies,
+		Chat:      chat,
 		StartedAt: matchRecord.StartedAt,
 		EndedAt:   matchRecord.EndedAt,
 	}
@@ -14,6 +14,7 @@ type MatchResultResponse struct {
 	OpponentRating float64 `json:"opponentRating"`
 	OpponentRD     float64 `json:"opponentRD"`
 	Result         float64 `json:"result"`
+	Color          string  `json:"color,omitempty"`
 	Timestamp      string  `json:"timestamp"`
 }
 
@@ -39,6 +40,7 @@ func MatchResultResponseFromEntity(matchResult entities.MatchResult) MatchResult
 		OpponentRating: matchResult.OpponentRating,
 		OpponentRD:     matchResult.OpponentRD,
 		Result:         matchResult.Result,
+		Color:          matchResult.Color,
 		Timestamp:      matchResult.Timestamp,
 	}
 }
@@ -26,8 +26,12 @@ type PlayerStateRequest struct {
 }
 
 type MoveRequest struct {
-	PlayerId string `json:"playerId"`
-	Uci      string `json:"uci"`
+	PlayerId    string    `json:"playerId"`
+	Uci         string    `json:"uci"`
+	TimeTaken   string    `json:"timeTaken"`
+	LagForgiven string    `json:"lagForgiven"`
+	Increment   string    `json:"increment"`
+	ReceivedAt  time.Time `json:"receivedAt"`
 }
 
 type MatchStateAppSyncRequest struct {
@@ -41,8 +45,12 @@ type PlayerStateResponse struct {
 }
 
 type MoveResponse struct {
-	PlayerId string `json:"playerId"`
-	Uci      string `json:"uci"`
+	PlayerId    string    `json:"playerId"`
+	Uci         string    `json:"uci"`
+	TimeTaken   string    `json:"timeTaken"`
+	LagForgiven string    `json:"lagForgiven"`
+	Increment   string    `json:"increment"`
+	ReceivedAt  time.Time `json:"receivedAt"`
 }
 
 type MatchStateResponse struct {
@@ -90,8 +98,12 @@ func MatchStateRequestToEntity(req MatchStateRequest) entities.MatchState {
 		},
 		GameState: req.GameState,
 		Move: entities.Move{
-			PlayerId: req.Move.PlayerId,
-			Uci:      req.Move.Uci,
+			PlayerId:    req.Move.PlayerId,
+			Uci:         req.Move.Uci,
+			TimeTaken:   req.Move.TimeTaken,
+			LagForgiven: req.Move.LagForgiven,
+			Increment:   req.Move.Increment,
+			ReceivedAt:  req.Move.ReceivedAt,
 		},
 		Ply:       req.Ply,
 		Timestamp: req.Timestamp,
@@ -114,8 +126,12 @@ func MatchStateResponseFromEntitiy(matchState entities.MatchState) MatchStateRes
 		},
 		GameState: matchState.GameState,
 		Move: MoveResponse{
-			PlayerId: matchState.Move.PlayerId,
-			Uci:      matchState.Move.Uci,
+			PlayerId:    matchState.Move.PlayerId,
+			Uci:         matchState.Move.Uci,
+			TimeTaken:   matchState.Move.TimeTaken,
+			LagForgiven: matchState.Move.LagForgiven,
+			Increment:   matchState.Move.Increment,
+			ReceivedAt:  matchState.Move.ReceivedAt,
 		},
 		Ply:       matchState.Ply,
 		Timestamp: matchState.Timestamp,
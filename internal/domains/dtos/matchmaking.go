@@ -2,18 +2,39 @@ package dtos
 
 import "github.com/chess-vn/slchess/internal/domains/entities"
 
+const QueueStatusType = "queueStatus"
+
 type MatchmakingRequest struct {
 	MinRating float64 `json:"minRating"`
 	MaxRating float64 `json:"maxRating"`
 	GameMode  string  `json:"gameMode"`
+	Casual    bool    `json:"casual"`
+	TeamMode  string  `json:"teamMode,omitempty"`
 }
 
 func MatchmakingRequestToEntity(userRating entities.UserRating, req MatchmakingRequest) entities.MatchmakingTicket {
-	return entities.MatchmakingTicket{
+	ticket := entities.MatchmakingTicket{
 		UserId:     userRating.UserId,
 		UserRating: userRating.Rating,
+		UserRD:     userRating.RD,
 		MinRating:  req.MinRating,
 		MaxRating:  req.MaxRating,
 		GameMode:   req.GameMode,
+		Pool:       entities.MatchmakingPoolRated,
+		TeamMode:   req.TeamMode,
+	}
+	if req.Casual {
+		ticket.Pool = entities.MatchmakingPoolCasual
 	}
+	return ticket
+}
+
+type QueueStatusResponse struct {
+	Type     string `json:"type"`
+	GameMode string `json:"gameMode"`
+	Casual   bool   `json:"casual"`
+	Position int    `json:"position"`
+	PoolSize int    `json:"poolSize"`
+	// In seconds, missing when no queued player is in range
+	EstimatedWait *int64 `json:"estimatedWait,omitempty"`
 }
@@ -0,0 +1,25 @@
+package dtos
+
+type RatingHistoryResponse struct {
+	UserId   string                       `json:"userId"`
+	Category string                       `json:"category"`
+	Points   []RatingHistoryPointResponse `json:"points"`
+	// Missing until the first rated game of the category
+	Peak *RatingPeakResponse `json:"peak,omitempty"`
+	// Rating gained since the start of the current month
+	MonthChange float64 `json:"monthChange"`
+}
+
+// RatingHistoryPointResponse is the rating of one day with games, Rating is the one after its last game
+type RatingHistoryPointResponse struct {
+	Date   string  `json:"date"`
+	Rating float64 `json:"rating"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Games  int     `json:"games"`
+}
+
+type RatingPeakResponse struct {
+	Rating float64 `json:"rating"`
+	Date   string  `json:"date"`
+}
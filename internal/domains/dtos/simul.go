@@ -0,0 +1,60 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/chess-vn/slchess/internal/domains/entities"
+)
+
+type SimulCreateRequest struct {
+	GameMode        string `json:"gameMode"`
+	ClockMode       string `json:"clockMode"`
+	HostClock       string `json:"hostClock,omitempty"`
+	MaxParticipants int    `json:"maxParticipants"`
+}
+
+type SimulResponse struct {
+	SimulId         string     `json:"simulId"`
+	HostId          string     `json:"hostId"`
+	GameMode        string     `json:"gameMode"`
+	ClockMode       string     `json:"clockMode"`
+	HostClock       string     `json:"hostClock,omitempty"`
+	MaxParticipants int        `json:"maxParticipants"`
+	Participants    []string   `json:"participants"`
+	MatchIds        []string   `json:"matchIds,omitempty"`
+	Status          string     `json:"status"`
+	Server          string     `json:"server,omitempty"`
+	StartedAt       *time.Time `json:"startedAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+func SimulCreateRequestToEntity(hostId string, req SimulCreateRequest) entities.Simul {
+	return entities.Simul{
+		HostId:          hostId,
+		GameMode:        req.GameMode,
+		ClockMode:       req.ClockMode,
+		HostClock:       req.HostClock,
+		MaxParticipants: req.MaxParticipants,
+		Participants:    []string{},
+		MatchIds:        []string{},
+		Status:          entities.SimulStatusOpen,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func SimulResponseFromEntity(simul entities.Simul) SimulResponse {
+	return SimulResponse{
+		SimulId:         simul.SimulId,
+		HostId:          simul.HostId,
+		GameMode:        simul.GameMode,
+		ClockMode:       simul.ClockMode,
+		HostClock:       simul.HostClock,
+		MaxParticipants: simul.MaxParticipants,
+		Participants:    simul.Participants,
+		MatchIds:        simul.MatchIds,
+		Status:          simul.Status,
+		Server:          simul.Server,
+		StartedAt:       simul.StartedAt,
+		CreatedAt:       simul.CreatedAt,
+	}
+}
@@ -7,24 +7,30 @@ import (
 )
 
 type UserResponse struct {
-	Id         string    `json:"id"`
-	Username   string    `json:"username"`
-	Phone      string    `json:"phone,omitempty"`
-	Locale     string    `json:"locale"`
-	Avatar     string    `json:"avatar"`
-	Rating     float64   `json:"rating"`
-	Membership string    `json:"membership"`
-	CreatedAt  time.Time `json:"createdAt"`
+	Id         string             `json:"id"`
+	Username   string             `json:"username"`
+	Phone      string             `json:"phone,omitempty"`
+	Locale     string             `json:"locale"`
+	Avatar     string             `json:"avatar"`
+	Ratings    map[string]float64 `json:"ratings"`
+	Membership string             `json:"membership"`
+	Guest      bool               `json:"guest,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
 }
 
-func UserResponseFromEntities(userProfile entities.UserProfile, userRating entities.UserRating, full bool) UserResponse {
+func UserResponseFromEntities(userProfile entities.UserProfile, userRatings []entities.UserRating, full bool) UserResponse {
+	ratings := make(map[string]float64, len(userRatings))
+	for _, userRating := range userRatings {
+		ratings[userRating.Category] = userRating.Rating
+	}
 	user := UserResponse{
 		Id:         userProfile.UserId,
 		Username:   userProfile.Username,
 		Locale:     userProfile.Locale,
 		Avatar:     userProfile.Avatar,
-		Rating:     userRating.Rating,
+		Ratings:    ratings,
 		Membership: userProfile.Membership,
+		Guest:      userProfile.Guest,
 		CreatedAt:  userProfile.CreatedAt,
 	}
 	if full {
@@ -10,15 +10,20 @@ type UserRatingResponse struct {
 }
 
 type UserRatingListResponse struct {
+	Category      string                   `json:"category"`
 	Items         []UserRatingResponse     `json:"items"`
 	NextPageToken *NextUserRatingPageToken `json:"nextPageToken"`
 }
 
+// NextUserRatingPageToken holds the last evaluated key of the rating index, the rating is kept as DynamoDB wrote it
 type NextUserRatingPageToken struct {
-	Rating string `json:"rating"`
+	UserId       string `json:"userId"`
+	Category     string `json:"category"`
+	PartitionKey string `json:"partitionKey"`
+	Rating       string `json:"rating"`
 }
 
-func UserRatingListResponseFromEntities(userRatings []entities.UserRating) UserRatingListResponse {
+func UserRatingListResponseFromEntities(category string, userRatings []entities.UserRating) UserRatingListResponse {
 	userRatingResponses := make([]UserRatingResponse, 0, len(userRatings))
 	for _, userRating := range userRatings {
 		userRatingResponses = append(userRatingResponses, UserRatingResponse{
@@ -28,6 +33,7 @@ func UserRatingListResponseFromEntities(userRatings []entities.UserRating) UserR
 		})
 	}
 	return UserRatingListResponse{
-		Items: userRatingResponses,
+		Category: category,
+		Items:    userRatingResponses,
 	}
 }
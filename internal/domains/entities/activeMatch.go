@@ -1,18 +1,37 @@
 package entities
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	TeamModeStandard     = "STANDARD"
+	TeamModeHandAndBrain = "HAND_AND_BRAIN"
+
+	// In hand-and-brain the brain names a piece type and the hand moves a piece of that type
+	RoleHand  = "HAND"
+	RoleBrain = "BRAIN"
+)
 
 type ActiveMatch struct {
-	MatchId        string     `dynamodbav:"MatchId"`
-	ConversationId string     `dynamodbav:"ConversationId"`
-	PartitionKey   string     `dynamodbav:"PartitionKey"`
-	Player1        Player     `dynamodbav:"Player1"`
-	Player2        Player     `dynamodbav:"Player2"`
-	GameMode       string     `dynamodbav:"GameMode"`
-	Server         string     `dynamodbav:"Server"`
-	AverageRating  float64    `dynamodbav:"AverageRating"`
-	StartedAt      *time.Time `dynamodbav:"StartedAt"`
-	CreatedAt      time.Time  `dynamodbav:"CreatedAt"`
+	MatchId        string       `dynamodbav:"MatchId"`
+	ConversationId string       `dynamodbav:"ConversationId"`
+	PartitionKey   string       `dynamodbav:"PartitionKey"`
+	Player1        Player       `dynamodbav:"Player1"`
+	Player2        Player       `dynamodbav:"Player2"`
+	Teammates      []Player     `dynamodbav:"Teammates,omitempty"`
+	TeamMode       string       `dynamodbav:"TeamMode,omitempty"`
+	SimulId        string       `dynamodbav:"SimulId,omitempty"`
+	Casual         bool         `dynamodbav:"Casual,omitempty"`
+	Adjournment    *Adjournment `dynamodbav:"Adjournment,omitempty"`
+	GameMode       string       `dynamodbav:"GameMode"`
+	Server         string       `dynamodbav:"Server"`
+	AverageRating  float64      `dynamodbav:"AverageRating"`
+	StartedAt      *time.Time   `dynamodbav:"StartedAt"`
+	CreatedAt      time.Time    `dynamodbav:"CreatedAt"`
+	// Player1 plays white, this records how that was decided
+	ColorAllocation string `dynamodbav:"ColorAllocation,omitempty"`
 }
 
 type Player struct {
@@ -22,4 +41,43 @@ type Player struct {
 	RD         float64   `dynamodbav:"RD"`
 	NewRatings []float64 `dynamodbav:"NewRatings"`
 	NewRDs     []float64 `dynamodbav:"NewRDs"`
+	Team       int       `dynamodbav:"Team"`
+	Role       string    `dynamodbav:"Role,omitempty"`
+	// Glicko-2 volatility, missing on unrated players
+	Volatility      float64   `dynamodbav:"Volatility,omitempty"`
+	NewVolatilities []float64 `dynamodbav:"NewVolatilities,omitempty"`
+	// Set on guests, whose provisional rating moves in casual games too
+	Provisional bool `dynamodbav:"Provisional,omitempty"`
+}
+
+// Adjournment holds an adjourned match until both players resume it
+type Adjournment struct {
+	Moves       []string  `dynamodbav:"Moves"`
+	MoveClocks  []string  `dynamodbav:"MoveClocks"`
+	Clocks      []string  `dynamodbav:"Clocks"`
+	AdjournedAt time.Time `dynamodbav:"AdjournedAt"`
+	Deadline    time.Time `dynamodbav:"Deadline"`
+}
+
+// Players method    returns every participant, Player1 and Player2 first
+func (m ActiveMatch) Players() []Player {
+	players := make([]Player, 0, 2+len(m.Teammates))
+	player1 := m.Player1
+	player1.Team = 0
+	player2 := m.Player2
+	player2.Team = 1
+	players = append(players, player1, player2)
+	return append(players, m.Teammates...)
+}
+
+// TeamSize function    returns the number of players on each side of a team mode, a missing mode is standard
+func TeamSize(teamMode string) (int, error) {
+	switch teamMode {
+	case "", TeamModeStandard:
+		return 1, nil
+	case TeamModeHandAndBrain:
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("unknown team mode: %s", teamMode)
+	}
 }
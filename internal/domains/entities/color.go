@@ -0,0 +1,19 @@
+package entities
+
+const (
+	ColorWhite = "WHITE"
+	ColorBlack = "BLACK"
+
+	// How the colors of a match were decided
+	ColorAllocationBalanced   = "BALANCED"
+	ColorAllocationRandom     = "RANDOM"
+	ColorAllocationPreference = "PREFERENCE"
+)
+
+// TeamColor function    returns the color of a team, the first team always plays white
+func TeamColor(team int) string {
+	if team == 0 {
+		return ColorWhite
+	}
+	return ColorBlack
+}
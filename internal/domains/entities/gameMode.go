@@ -12,14 +12,17 @@ type GameMode struct {
 	Increment time.Duration
 }
 
+// Grouped by the rating category of their estimated duration
 var gameModes = []string{
-	"1+0", "1+1", "1+2", // Bullet
-	"2+1", "2+2", // Bullet
-	"3+0", "3+2", // Blitz
-	"5+0", "5+3", "5+5", // Blitz
-	"10+0", "10+5", // Rapid
-	"15+10", "25+10", // Rapid
-	"30+0", "45+15", "60+30", // Classical
+	"1+0", "1+1", "1+2", "2+1", // Bullet
+	"2+2", "3+0", "3+2", "5+0", "5+3", // Blitz
+	"5+5", "10+0", "10+5", "15+10", // Rapid
+	"25+10", "30+0", "45+15", "60+30", // Classical
+}
+
+// GameModes function    lists every game mode players can queue for
+func GameModes() []string {
+	return append([]string(nil), gameModes...)
 }
 
 func ValidateGameMode(gameMode string) error {
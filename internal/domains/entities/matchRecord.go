@@ -6,12 +6,54 @@ type PlayerRecord struct {
 	Id        string  `dynamodbav:"Id"`
 	OldRating float64 `dynamodbav:"Rating"`
 	NewRating float64 `dynamodbav:"NewRating"`
+	OldRD     float64 `dynamodbav:"OldRD,omitempty"`
+	NewRD     float64 `dynamodbav:"NewRD,omitempty"`
+	Team      int     `dynamodbav:"Team"`
+	// Casual games and games without an outcome leave the rating as it was,
+	// players of records written before it was kept are all rated
+	Unrated bool `dynamodbav:"Unrated,omitempty"`
+}
+
+// Rated method    reports whether the game changed the rating of any of its players
+func (r MatchRecord) Rated() bool {
+	for _, player := range r.Players {
+		if !player.Unrated {
+			return true
+		}
+	}
+	return false
+}
+
+type PlyRecord struct {
+	Ply         int       `dynamodbav:"Ply"`
+	PlayerId    string    `dynamodbav:"PlayerId"`
+	Uci         string    `dynamodbav:"Uci"`
+	Clock       string    `dynamodbav:"Clock"`
+	TimeTaken   string    `dynamodbav:"TimeTaken"`
+	LagForgiven string    `dynamodbav:"LagForgiven"`
+	Increment   string    `dynamodbav:"Increment"`
+	ReceivedAt  time.Time `dynamodbav:"ReceivedAt"`
+}
+
+type ChatMessageRecord struct {
+	PlayerId  string    `dynamodbav:"PlayerId"`
+	Username  string    `dynamodbav:"Username"`
+	Content   string    `dynamodbav:"Content"`
+	Quick     string    `dynamodbav:"Quick"`
+	CreatedAt time.Time `dynamodbav:"CreatedAt"`
 }
 
 type MatchRecord struct {
-	MatchId   string         `dynamodbav:"MatchId"`
-	Players   []PlayerRecord `dynamodbav:"Players"`
-	Pgn       string         `dynamodbav:"Pgn"`
-	StartedAt time.Time      `dynamodbav:"StartedAt"`
-	EndedAt   time.Time      `dynamodbav:"EndedAt"`
+	MatchId   string              `dynamodbav:"MatchId"`
+	GameMode  string              `dynamodbav:"GameMode"`
+	Players   []PlayerRecord      `dynamodbav:"Players"`
+	Pgn       string              `dynamodbav:"Pgn"`
+	Plies     []PlyRecord         `dynamodbav:"Plies"`
+	Chat      []ChatMessageRecord `dynamodbav:"Chat"`
+	StartedAt time.Time           `dynamodbav:"StartedAt"`
+	EndedAt   time.Time           `dynamodbav:"EndedAt"`
+	// Score of each player, missing on records written before it was kept
+	Results []float64 `dynamodbav:"Results,omitempty"`
+	// Played in the casual pool or in a simul
+	Casual bool `dynamodbav:"Casual,omitempty"`
 }
@@ -8,4 +8,6 @@ type MatchResult struct {
 	OpponentRD     float64 `dynamodbav:"OpponentRD"`
 	Result         float64 `dynamodbav:"Result"`
 	Timestamp      string  `dynamodbav:"Timestamp"`
+	// Color the user played, missing on results recorded before it was tracked
+	Color string `dynamodbav:"Color,omitempty"`
 }
@@ -8,8 +8,12 @@ type PlayerState struct {
 }
 
 type Move struct {
-	PlayerId string `dynamodbav:"PlayerId"`
-	Uci      string `dynamodbav:"Uci"`
+	PlayerId    string    `dynamodbav:"PlayerId"`
+	Uci         string    `dynamodbav:"Uci"`
+	TimeTaken   string    `dynamodbav:"TimeTaken"`
+	LagForgiven string    `dynamodbav:"LagForgiven"`
+	Increment   string    `dynamodbav:"Increment"`
+	ReceivedAt  time.Time `dynamodbav:"ReceivedAt"`
 }
 
 type MatchState struct {
@@ -2,22 +2,61 @@ package entities
 
 import (
 	"fmt"
+	"time"
+)
+
+const (
+	MatchmakingPoolRated  = "RATED"
+	MatchmakingPoolCasual = "CASUAL"
+
+	// A ticket without a heartbeat for this long is left out of matchmaking and expires from the table
+	MatchmakingTicketLifetime = 90 * time.Second
 )
 
 type MatchmakingTicket struct {
 	UserId     string  `dynamodbav:"UserId"`
 	UserRating float64 `dynamodbav:"UserRating"`
-	MinRating  float64 `dynamodbav:"MinRating"`
-	MaxRating  float64 `dynamodbav:"MaxRating"`
-	GameMode   string  `dynamodbav:"GameMode"`
+	UserRD     float64 `dynamodbav:"UserRD"`
+	// Optional hard limits chosen by the client, zero on both sides means no limit
+	MinRating  float64   `dynamodbav:"MinRating"`
+	MaxRating  float64   `dynamodbav:"MaxRating"`
+	GameMode   string    `dynamodbav:"GameMode"`
+	Pool       string    `dynamodbav:"Pool"`
+	TeamMode   string    `dynamodbav:"TeamMode,omitempty"`
+	EnqueuedAt time.Time `dynamodbav:"EnqueuedAt"`
+	// Refreshed while the queueing socket is open
+	HeartbeatAt time.Time `dynamodbav:"HeartbeatAt"`
+	TTL         int64     `dynamodbav:"TTL"`
+}
+
+// Refresh method    records a heartbeat and pushes the expiry of the ticket further
+func (t *MatchmakingTicket) Refresh(now time.Time) {
+	t.HeartbeatAt = now
+	t.TTL = now.Add(MatchmakingTicketLifetime).Unix()
+}
+
+// Expired method    reports whether the ticket missed its heartbeats, the table TTL may take a while to remove it
+func (t *MatchmakingTicket) Expired(now time.Time) bool {
+	return now.Sub(t.HeartbeatAt) > MatchmakingTicketLifetime
+}
+
+// HasRatingRange method    reports whether the client limited the opponent rating
+func (t *MatchmakingTicket) HasRatingRange() bool {
+	return t.MinRating != 0 || t.MaxRating != 0
 }
 
 func (t *MatchmakingTicket) Validate() error {
-	if t.UserRating < t.MinRating || t.UserRating > t.MaxRating {
+	if t.HasRatingRange() && (t.UserRating < t.MinRating || t.UserRating > t.MaxRating) {
 		return fmt.Errorf("invalid rating range: %v-%v-%v", t.MinRating, t.UserRating, t.MaxRating)
 	}
 	if err := ValidateGameMode(t.GameMode); err != nil {
 		return fmt.Errorf("invalid game mode: %v", err)
 	}
+	if t.Pool != MatchmakingPoolRated && t.Pool != MatchmakingPoolCasual {
+		return fmt.Errorf("invalid pool: %s", t.Pool)
+	}
+	if _, err := TeamSize(t.TeamMode); err != nil {
+		return fmt.Errorf("invalid team mode: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// Players hold a separate rating in each category
+const (
+	RatingCategoryBullet         = "bullet"
+	RatingCategoryBlitz          = "blitz"
+	RatingCategoryRapid          = "rapid"
+	RatingCategoryClassical      = "classical"
+	RatingCategoryCorrespondence = "correspondence"
+	// Non-standard variants share one rating whatever the clock
+	RatingCategoryVariants = "variants"
+)
+
+// Moves assumed when estimating the duration of a game from its clock
+const estimatedMoves = 40
+
+var ratingCategories = []string{
+	RatingCategoryBullet,
+	RatingCategoryBlitz,
+	RatingCategoryRapid,
+	RatingCategoryClassical,
+	RatingCategoryCorrespondence,
+	RatingCategoryVariants,
+}
+
+// RatingCategories function    lists every rating category
+func RatingCategories() []string {
+	return append([]string(nil), ratingCategories...)
+}
+
+func ValidateRatingCategory(category string) error {
+	for _, c := range ratingCategories {
+		if category == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown rating category")
+}
+
+// RatingCategoryOf function    returns the rating category of a game mode
+func RatingCategoryOf(gameMode string) (string, error) {
+	gm, err := ParseGameMode(gameMode)
+	if err != nil {
+		return "", err
+	}
+	return gm.RatingCategory(), nil
+}
+
+// EstimatedDuration method    returns the clock time of one player over a game of average length
+func (gm GameMode) EstimatedDuration() time.Duration {
+	return gm.Time + estimatedMoves*gm.Increment
+}
+
+// RatingCategory method    returns the rating category of the game mode from its estimated duration
+func (gm GameMode) RatingCategory() string {
+	switch d := gm.EstimatedDuration(); {
+	case d < 3*time.Minute:
+		return RatingCategoryBullet
+	case d < 8*time.Minute:
+		return RatingCategoryBlitz
+	case d < 25*time.Minute:
+		return RatingCategoryRapid
+	case d < 24*time.Hour:
+		return RatingCategoryClassical
+	default:
+		return RatingCategoryCorrespondence
+	}
+}
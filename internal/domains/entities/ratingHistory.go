@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// RatingHistoryEntry is the rating of a user in a category after one rated game
+type RatingHistoryEntry struct {
+	UserId string `dynamodbav:"UserId"`
+	// Category, end of the game and match id, sorts the entries of a category by time
+	SortKey   string    `dynamodbav:"SortKey"`
+	Category  string    `dynamodbav:"Category"`
+	MatchId   string    `dynamodbav:"MatchId"`
+	OldRating float64   `dynamodbav:"OldRating"`
+	Rating    float64   `dynamodbav:"Rating"`
+	RD        float64   `dynamodbav:"RD"`
+	RatedAt   time.Time `dynamodbav:"RatedAt"`
+}
+
+// NewRatingHistoryEntry function    returns the history entry of a rated game
+func NewRatingHistoryEntry(
+	userId string,
+	category string,
+	matchId string,
+	oldRating float64,
+	rating float64,
+	rd float64,
+	ratedAt time.Time,
+) RatingHistoryEntry {
+	return RatingHistoryEntry{
+		UserId:    userId,
+		SortKey:   RatingHistorySortKey(category, ratedAt) + "#" + matchId,
+		Category:  category,
+		MatchId:   matchId,
+		OldRating: oldRating,
+		Rating:    rating,
+		RD:        rd,
+		RatedAt:   ratedAt,
+	}
+}
+
+// RatingHistorySortKey function    returns the sort key prefix of the entries of a category rated at a time
+func RatingHistorySortKey(category string, ratedAt time.Time) string {
+	return strings.Join([]string{category, ratedAt.UTC().Format(time.RFC3339)}, "#")
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	SimulStatusOpen    = "OPEN"
+	SimulStatusStarted = "STARTED"
+	SimulStatusEnded   = "ENDED"
+
+	SimulClockNone   = "NONE"
+	SimulClockShared = "SHARED"
+)
+
+type Simul struct {
+	SimulId         string     `dynamodbav:"SimulId"`
+	HostId          string     `dynamodbav:"HostId"`
+	GameMode        string     `dynamodbav:"GameMode"`
+	ClockMode       string     `dynamodbav:"ClockMode"`
+	HostClock       string     `dynamodbav:"HostClock,omitempty"`
+	MaxParticipants int        `dynamodbav:"MaxParticipants"`
+	Participants    []string   `dynamodbav:"Participants"`
+	MatchIds        []string   `dynamodbav:"MatchIds"`
+	Status          string     `dynamodbav:"Status"`
+	Server          string     `dynamodbav:"Server"`
+	StartedAt       *time.Time `dynamodbav:"StartedAt"`
+	CreatedAt       time.Time  `dynamodbav:"CreatedAt"`
+}
+
+func (s *Simul) Validate() error {
+	if err := ValidateGameMode(s.GameMode); err != nil {
+		return fmt.Errorf("invalid game mode: %v", err)
+	}
+	switch s.ClockMode {
+	case SimulClockNone:
+	case SimulClockShared:
+		if _, err := time.ParseDuration(s.HostClock); err != nil {
+			return fmt.Errorf("invalid host clock: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid clock mode: %s", s.ClockMode)
+	}
+	if s.MaxParticipants < 1 || s.MaxParticipants > 50 {
+		return fmt.Errorf("invalid max participants: %d", s.MaxParticipants)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package entities
+
+import "time"
+
+type UserBlock struct {
+	UserId    string    `dynamodbav:"UserId"`
+	BlockedId string    `dynamodbav:"BlockedId"`
+	CreatedAt time.Time `dynamodbav:"CreatedAt"`
+}
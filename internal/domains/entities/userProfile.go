@@ -10,4 +10,12 @@ type UserProfile struct {
 	Locale     string    `dynamodbav:"Locale"`
 	Membership string    `dynamodbav:"Membership"`
 	CreatedAt  time.Time `dynamodbav:"CreatedAt"`
+
+	// Number of games aborted because the user never made a first move
+	MissedFirstMoves      int        `dynamodbav:"MissedFirstMoves"`
+	LastMissedFirstMoveAt *time.Time `dynamodbav:"LastMissedFirstMoveAt,omitempty"`
+
+	// Anonymous guest profiles expire through the table TTL unless converted to an account
+	Guest bool  `dynamodbav:"Guest,omitempty"`
+	TTL   int64 `dynamodbav:"TTL,omitempty"`
 }
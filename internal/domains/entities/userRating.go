@@ -1,9 +1,61 @@
 package entities
 
+import (
+	"strings"
+	"time"
+)
+
+const (
+	UserRatingsPartitionKey  = "UserRatings"
+	GuestRatingsPartitionKey = "GuestRatings"
+)
+
+// Rating given to every category of a new player
+const (
+	InitialRating = 1200.0
+	InitialRD     = 100.0
+)
+
 type UserRating struct {
-	UserId       string  `dynamodbav:"UserId"`
-	Username     string  `dynamodbav:"Username"`
+	UserId   string `dynamodbav:"UserId"`
+	Category string `dynamodbav:"Category"`
+	Username string `dynamodbav:"Username"`
+	// Scoped to the category, so that each category has its own leaderboard
 	PartitionKey string  `dynamodbav:"PartitionKey"`
 	Rating       float64 `dynamodbav:"Rating"`
 	RD           float64 `dynamodbav:"RD"`
+	TTL          int64   `dynamodbav:"TTL,omitempty"`
+	// Glicko-2 volatility, missing until the first game rated with it
+	Volatility float64 `dynamodbav:"Volatility,omitempty"`
+	// End of the last rated game, the RD grows with the time since
+	RatedAt *time.Time `dynamodbav:"RatedAt,omitempty"`
+}
+
+// IsGuest method    reports whether the rating belongs to an anonymous guest, guests are kept off the leaderboard
+func (r UserRating) IsGuest() bool {
+	return strings.HasPrefix(r.PartitionKey, GuestRatingsPartitionKey)
+}
+
+// RatingsPartitionKey function    returns the leaderboard partition of a category
+func RatingsPartitionKey(category string, guest bool) string {
+	if guest {
+		return GuestRatingsPartitionKey + "#" + category
+	}
+	return UserRatingsPartitionKey + "#" + category
+}
+
+// InitialUserRatings function    returns the ratings of a new player, one per category
+func InitialUserRatings(userId, username string, guest bool) []UserRating {
+	userRatings := make([]UserRating, 0, len(ratingCategories))
+	for _, category := range ratingCategories {
+		userRatings = append(userRatings, UserRating{
+			UserId:       userId,
+			Category:     category,
+			Username:     username,
+			PartitionKey: RatingsPartitionKey(category, guest),
+			Rating:       InitialRating,
+			RD:           InitialRD,
+		})
+	}
+	return userRatings
 }
@@ -0,0 +1,120 @@
+package pgn
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/opening"
+)
+
+var (
+	ecoBook     *opening.BookECO
+	ecoBookOnce sync.Once
+)
+
+const standardStartFen = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// Game struct    holds everything the PGN export of a game needs
+type Game struct {
+	Event       string
+	Date        time.Time
+	White       string
+	Black       string
+	WhiteElo    float64
+	BlackElo    float64
+	TimeControl string
+	Termination string
+	Outcome     chess.Outcome
+	Positions   []*chess.Position
+	Moves       []*chess.Move
+	// Clock after every move, nil for untimed games
+	Clocks []time.Duration
+}
+
+type tagPair struct {
+	key   string
+	value string
+}
+
+/*
+Write function    returns the complete PGN of the game.
+It carries the Seven Tag Roster, ratings, time control, termination and opening,
+and a [%clk] comment after every move.
+*/
+func Write(g Game) string {
+	date := g.Date.UTC()
+	tags := []tagPair{
+		{"Event", g.Event},
+		{"Site", "slchess"},
+		{"Date", date.Format("2006.01.02")},
+		{"Round", "-"},
+		{"White", g.White},
+		{"Black", g.Black},
+		{"Result", g.Outcome.String()},
+		{"UTCDate", date.Format("2006.01.02")},
+		{"UTCTime", date.Format("15:04:05")},
+		{"WhiteElo", fmt.Sprintf("%.0f", math.Round(g.WhiteElo))},
+		{"BlackElo", fmt.Sprintf("%.0f", math.Round(g.BlackElo))},
+		{"TimeControl", g.TimeControl},
+		{"Termination", g.Termination},
+	}
+	// Matches resumed from a saved FEN do not start from the initial position
+	if startFen := g.Positions[0].String(); startFen != standardStartFen {
+		tags = append(tags, tagPair{"SetUp", "1"}, tagPair{"FEN", startFen})
+	}
+	if o := findOpening(g.Moves); o != nil {
+		tags = append(tags, tagPair{"ECO", o.Code()}, tagPair{"Opening", o.Title()})
+	}
+
+	var sb strings.Builder
+	for _, tag := range tags {
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", tag.key, escapeTag(tag.value))
+	}
+	sb.WriteString("\n")
+	for i, move := range g.Moves {
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, "%d. ", i/2+1)
+		}
+		sb.WriteString(chess.AlgebraicNotation{}.Encode(g.Positions[i], move))
+		if i < len(g.Clocks) {
+			fmt.Fprintf(&sb, " { [%%clk %s] }", formatClock(g.Clocks[i]))
+		}
+		sb.WriteString(" ")
+	}
+	sb.WriteString(g.Outcome.String())
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// TimeControl function    returns the PGN time control, base and increment in seconds
+func TimeControl(base, increment time.Duration) string {
+	return fmt.Sprintf("%d+%d", int(base.Seconds()), int(increment.Seconds()))
+}
+
+func findOpening(moves []*chess.Move) *opening.Opening {
+	if len(moves) == 0 {
+		return nil
+	}
+	ecoBookOnce.Do(func() {
+		ecoBook = opening.NewBookECO()
+	})
+	return ecoBook.Find(moves)
+}
+
+// formatClock function    formats a clock as H:MM:SS like lichess exports
+func formatClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func escapeTag(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
@@ -0,0 +1,180 @@
+package rating
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Factor between the Glicko and the Glicko-2 scales
+	glicko2Scale = 173.7178
+	// Rating at the center of the Glicko-2 scale
+	glicko2Center = 1500.0
+	// Convergence tolerance of the volatility iteration
+	convergence = 0.000001
+
+	Win  = 1.0
+	Draw = 0.5
+	Loss = 0.0
+)
+
+// Rating struct    is a player's rating on the Glicko scale
+type Rating struct {
+	Rating     float64
+	RD         float64
+	Volatility float64
+}
+
+// Result struct    is the score of one game against an opponent, from the player's side
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Config struct    holds the system parameters of the rating system
+type Config struct {
+	// Constrains the change in volatility over time, reasonable values are between 0.3 and 1.2
+	Tau               float64
+	MinRD             float64
+	MaxRD             float64
+	InitialVolatility float64
+	// Inactivity raises the RD once for every rating period that passed
+	RatingPeriod time.Duration
+}
+
+var DefaultConfig = Config{
+	Tau:               0.5,
+	MinRD:             45,
+	MaxRD:             350,
+	InitialVolatility: 0.06,
+	RatingPeriod:      24 * time.Hour,
+}
+
+/*
+Update method    rates a player after one rating period, as described in Glickman's Glicko-2 paper.
+A period without results only raises the RD, the way an inactive player becomes less certain.
+*/
+func (c Config) Update(r Rating, results []Result) Rating {
+	r = c.withDefaults(r)
+	mu, phi := toGlicko2(r)
+	sigma := r.Volatility
+	if len(results) == 0 {
+		return c.Clamp(fromGlicko2(mu, math.Sqrt(phi*phi+sigma*sigma), sigma))
+	}
+
+	// Estimated variance of the rating based on the game outcomes only, and the improvement it brings
+	var vInv, sum float64
+	for _, result := range results {
+		muJ, phiJ := toGlicko2(c.withDefaults(result.Opponent))
+		g := g(phiJ)
+		e := expectedScore(mu, muJ, g)
+		vInv += g * g * e * (1 - e)
+		sum += g * (result.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma = c.volatility(phi, sigma, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*sum
+	return c.Clamp(fromGlicko2(newMu, newPhi, sigma))
+}
+
+// Decay method    raises the RD of a player for the rating periods passed without games
+func (c Config) Decay(r Rating, elapsed time.Duration) Rating {
+	r = c.withDefaults(r)
+	if elapsed <= 0 || c.RatingPeriod <= 0 {
+		return r
+	}
+	periods := float64(elapsed) / float64(c.RatingPeriod)
+	mu, phi := toGlicko2(r)
+	phi = math.Sqrt(phi*phi + periods*r.Volatility*r.Volatility)
+	return c.Clamp(fromGlicko2(mu, phi, r.Volatility))
+}
+
+// Outcomes method    returns the rating of a player after a win, a draw and a loss against an opponent
+func (c Config) Outcomes(r Rating, opponent Rating) [3]Rating {
+	var outcomes [3]Rating
+	for i, score := range []float64{Win, Draw, Loss} {
+		outcomes[i] = c.Update(r, []Result{{Opponent: opponent, Score: score}})
+	}
+	return outcomes
+}
+
+// Clamp method    keeps the RD within the configured bounds
+func (c Config) Clamp(r Rating) Rating {
+	r = c.withDefaults(r)
+	if c.MaxRD > 0 {
+		r.RD = math.Min(r.RD, c.MaxRD)
+	}
+	r.RD = math.Max(r.RD, c.MinRD)
+	return r
+}
+
+// withDefaults method    fills in the values missing on ratings created before volatility was tracked
+func (c Config) withDefaults(r Rating) Rating {
+	if r.Volatility <= 0 {
+		r.Volatility = c.InitialVolatility
+	}
+	if r.RD <= 0 {
+		r.RD = c.MaxRD
+	}
+	return r
+}
+
+// volatility method    finds the new volatility with the Illinois algorithm of the Glicko-2 paper
+func (c Config) volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	tau := c.Tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expectedScore(mu, muJ, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+func toGlicko2(r Rating) (float64, float64) {
+	return (r.Rating - glicko2Center) / glicko2Scale, r.RD / glicko2Scale
+}
+
+func fromGlicko2(mu, phi, sigma float64) Rating {
+	return Rating{
+		Rating:     mu*glicko2Scale + glicko2Center,
+		RD:         phi * glicko2Scale,
+		Volatility: sigma,
+	}
+}
@@ -0,0 +1,121 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Example of section 3 of Glickman's "Example of the Glicko-2 system"
+var glickmanPlayer = Rating{Rating: 1500, RD: 200, Volatility: 0.06}
+
+var glickmanResults = []Result{
+	{Opponent: Rating{Rating: 1400, RD: 30}, Score: Win},
+	{Opponent: Rating{Rating: 1550, RD: 100}, Score: Loss},
+	{Opponent: Rating{Rating: 1700, RD: 300}, Score: Loss},
+}
+
+// The paper's example has no RD bounds
+var glickmanConfig = Config{
+	Tau:               0.5,
+	MaxRD:             350,
+	InitialVolatility: 0.06,
+	RatingPeriod:      24 * time.Hour,
+}
+
+func assertClose(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %.6f, want %.6f (±%g)", name, got, want, tolerance)
+	}
+}
+
+func TestUpdateGlickmanExample(t *testing.T) {
+	got := glickmanConfig.Update(glickmanPlayer, glickmanResults)
+	assertClose(t, "rating", got.Rating, 1464.06, 0.01)
+	assertClose(t, "rd", got.RD, 151.52, 0.01)
+	assertClose(t, "volatility", got.Volatility, 0.05999, 0.00001)
+}
+
+func TestUpdateGlickmanIntermediateValues(t *testing.T) {
+	mu, phi := toGlicko2(glickmanPlayer)
+	assertClose(t, "mu", mu, 0, 0.0001)
+	assertClose(t, "phi", phi, 1.1513, 0.0001)
+
+	var vInv, sum float64
+	for _, result := range glickmanResults {
+		muJ, phiJ := toGlicko2(glickmanConfig.withDefaults(result.Opponent))
+		gJ := g(phiJ)
+		e := expectedScore(mu, muJ, gJ)
+		vInv += gJ * gJ * e * (1 - e)
+		sum += gJ * (result.Score - e)
+	}
+	v := 1 / vInv
+	// The paper rounds g and E before summing, hence the looser tolerance
+	assertClose(t, "v", v, 1.7785, 0.001)
+	assertClose(t, "delta", v*sum, -0.4834, 0.001)
+	assertClose(
+		t,
+		"sigma'",
+		glickmanConfig.volatility(phi, glickmanPlayer.Volatility, v, v*sum),
+		0.05999,
+		0.00001,
+	)
+}
+
+func TestUpdateWithoutGames(t *testing.T) {
+	got := glickmanConfig.Update(glickmanPlayer, nil)
+	assertClose(t, "rating", got.Rating, 1500, 0.000001)
+	// phi' = sqrt(1.1513^2 + 0.06^2)
+	assertClose(t, "rd", got.RD, 200.27, 0.01)
+	assertClose(t, "volatility", got.Volatility, 0.06, 0.000001)
+}
+
+func TestDecayMatchesEmptyPeriods(t *testing.T) {
+	want := glickmanPlayer
+	for range 3 {
+		want = glickmanConfig.Update(want, nil)
+	}
+	got := glickmanConfig.Decay(glickmanPlayer, 3*glickmanConfig.RatingPeriod)
+	assertClose(t, "rd", got.RD, want.RD, 0.000001)
+	assertClose(t, "rating", got.Rating, want.Rating, 0.000001)
+}
+
+func TestDecayIsBoundedByMaxRD(t *testing.T) {
+	got := DefaultConfig.Decay(glickmanPlayer, 100*365*24*time.Hour)
+	assertClose(t, "rd", got.RD, DefaultConfig.MaxRD, 0.000001)
+}
+
+func TestUpdateIsBoundedByMinRD(t *testing.T) {
+	r := Rating{Rating: 1500, RD: 100, Volatility: 0.06}
+	results := make([]Result, 0, 100)
+	for range 100 {
+		results = append(results, Result{Opponent: Rating{Rating: 1500, RD: 50}, Score: Draw})
+	}
+	got := DefaultConfig.Update(r, results)
+	assertClose(t, "rd", got.RD, DefaultConfig.MinRD, 0.000001)
+}
+
+func TestMissingVolatilityUsesInitialVolatility(t *testing.T) {
+	withoutVolatility := glickmanPlayer
+	withoutVolatility.Volatility = 0
+	got := glickmanConfig.Update(withoutVolatility, glickmanResults)
+	want := glickmanConfig.Update(glickmanPlayer, glickmanResults)
+	if got != want {
+		t.Errorf("update = %+v, want %+v", got, want)
+	}
+}
+
+func TestOutcomesAreOrdered(t *testing.T) {
+	outcomes := DefaultConfig.Outcomes(
+		Rating{Rating: 1200, RD: 100},
+		Rating{Rating: 1250, RD: 80},
+	)
+	if !(outcomes[0].Rating > outcomes[1].Rating && outcomes[1].Rating > outcomes[2].Rating) {
+		t.Errorf("outcomes not ordered win > draw > loss: %+v", outcomes)
+	}
+	// Drawing a stronger opponent gains rating
+	if outcomes[1].Rating <= 1200 {
+		t.Errorf("draw against a stronger opponent = %.2f, want above 1200", outcomes[1].Rating)
+	}
+}
@@ -0,0 +1,71 @@
+package rating
+
+import "math"
+
+// Team method    merges the ratings of a team into one, the average of each value
+func (c Config) Team(members []Rating) Rating {
+	var team Rating
+	if len(members) == 0 {
+		return c.withDefaults(team)
+	}
+	for _, member := range members {
+		member = c.withDefaults(member)
+		team.Rating += member.Rating
+		team.RD += member.RD
+		team.Volatility += member.Volatility
+	}
+	n := float64(len(members))
+	team.Rating /= n
+	team.RD /= n
+	team.Volatility /= n
+	return team
+}
+
+/*
+TeamUpdate method    rates every member of a team after one game against another team.
+The team is rated once as a single player against the opposing team, and every member
+takes the same change of rating, RD and volatility. A team of one is rated like a single player.
+*/
+func (c Config) TeamUpdate(members []Rating, opponents []Rating, score float64) []Rating {
+	before := c.Team(members)
+	after := c.Update(before, []Result{{Opponent: c.Team(opponents), Score: score}})
+	if len(members) == 1 {
+		return []Rating{after}
+	}
+	clamped := make([]Rating, 0, len(members))
+	for _, member := range members {
+		clamped = append(clamped, c.Clamp(member))
+	}
+	rdChange := c.clampTeamRDChange(clamped, after.RD-before.RD)
+	updated := make([]Rating, 0, len(members))
+	for _, member := range clamped {
+		updated = append(updated, Rating{
+			Rating:     member.Rating + after.Rating - before.Rating,
+			RD:         member.RD + rdChange,
+			Volatility: member.Volatility + after.Volatility - before.Volatility,
+		})
+	}
+	return updated
+}
+
+// clampTeamRDChange method    limits the shared RD change so that every member stays within the RD bounds
+func (c Config) clampTeamRDChange(members []Rating, change float64) float64 {
+	for _, member := range members {
+		if c.MaxRD > 0 {
+			change = math.Min(change, c.MaxRD-member.RD)
+		}
+		change = math.Max(change, c.MinRD-member.RD)
+	}
+	return change
+}
+
+// TeamOutcomes method    returns the rating of each team member after a win, a draw and a loss
+func (c Config) TeamOutcomes(members []Rating, opponents []Rating) [][3]Rating {
+	outcomes := make([][3]Rating, len(members))
+	for i, score := range []float64{Win, Draw, Loss} {
+		for j, updated := range c.TeamUpdate(members, opponents, score) {
+			outcomes[j][i] = updated
+		}
+	}
+	return outcomes
+}
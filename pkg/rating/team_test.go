@@ -0,0 +1,58 @@
+package rating
+
+import "testing"
+
+var (
+	teamMembers = []Rating{
+		{Rating: 1400, RD: 80, Volatility: 0.06},
+		{Rating: 1600, RD: 120, Volatility: 0.05},
+	}
+	teamOpponents = []Rating{
+		{Rating: 1450, RD: 60, Volatility: 0.06},
+		{Rating: 1550, RD: 100, Volatility: 0.06},
+	}
+)
+
+func TestTeamOfOneIsRatedAlone(t *testing.T) {
+	opponent := Rating{Rating: 1400, RD: 30}
+	got := DefaultConfig.TeamUpdate([]Rating{glickmanPlayer}, []Rating{opponent}, Win)
+	want := DefaultConfig.Update(glickmanPlayer, []Result{{Opponent: opponent, Score: Win}})
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("team update = %+v, want [%+v]", got, want)
+	}
+}
+
+func TestTeamMembersShareTheTeamChange(t *testing.T) {
+	team := DefaultConfig.Team(teamMembers)
+	after := DefaultConfig.Update(team, []Result{{Opponent: DefaultConfig.Team(teamOpponents), Score: Loss}})
+	got := DefaultConfig.TeamUpdate(teamMembers, teamOpponents, Loss)
+	for i, member := range teamMembers {
+		assertClose(t, "rating change", got[i].Rating-member.Rating, after.Rating-team.Rating, 0.000001)
+		assertClose(t, "rd change", got[i].RD-member.RD, after.RD-team.RD, 0.000001)
+		assertClose(t, "volatility change", got[i].Volatility-member.Volatility, after.Volatility-team.Volatility, 0.000001)
+	}
+}
+
+func TestTeamOutcomesAreOrdered(t *testing.T) {
+	for i, outcomes := range DefaultConfig.TeamOutcomes(teamMembers, teamOpponents) {
+		if !(outcomes[0].Rating > outcomes[1].Rating && outcomes[1].Rating > outcomes[2].Rating) {
+			t.Errorf("outcomes of member %d not ordered win > draw > loss: %+v", i, outcomes)
+		}
+	}
+}
+
+func TestTeamMemberAtRDBoundKeepsTheSharedChange(t *testing.T) {
+	members := []Rating{
+		{Rating: 1500, RD: DefaultConfig.MinRD, Volatility: 0.06},
+		{Rating: 1500, RD: 200, Volatility: 0.06},
+	}
+	got := DefaultConfig.TeamUpdate(members, teamOpponents, Win)
+	for i, member := range members {
+		if got[i].RD < DefaultConfig.MinRD || got[i].RD > DefaultConfig.MaxRD {
+			t.Errorf("rd of member %d = %.6f, out of bounds", i, got[i].RD)
+		}
+		assertClose(t, "rating change", got[i].Rating-member.Rating, got[0].Rating-members[0].Rating, 0.000001)
+		assertClose(t, "rd change", got[i].RD-member.RD, got[0].RD-members[0].RD, 0.000001)
+		assertClose(t, "volatility change", got[i].Volatility-member.Volatility, got[0].Volatility-members[0].Volatility, 0.000001)
+	}
+}